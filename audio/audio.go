@@ -29,21 +29,42 @@ package audio
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/internal/ui"
 	"github.com/hajimehoshi/oto"
 )
 
 type players struct {
-	players  map[*Player]struct{}
-	seekings map[*Player]struct{}
+	players map[*Player]struct{}
+	paused  map[*Player]struct{} // paused is the players paused by Context.PauseAll.
+	latency time.Duration
+
+	// maxDecodeWorkers is the maximum number of goroutines to read (decode) the sources concurrently.
+	maxDecodeWorkers int
+
+	// limiterReduction is the current gain reduction of the limiter in [0, 1). See limit.
+	limiterReduction float64
+
 	sync.RWMutex
 }
 
+// defaultMaxDecodeWorkers returns the default number of the decode workers.
+// Half of the processors are used so that the game loop is not disturbed.
+func defaultMaxDecodeWorkers() int {
+	n := runtime.GOMAXPROCS(0) / 2
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
 const (
 	channelNum     = 2
 	bytesPerSample = 2
@@ -52,11 +73,57 @@ const (
 	mask = ^(channelNum*bytesPerSample - 1)
 )
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// BytesPerSample is the size in bytes of one sample of a stream played by Player.
+//
+// A sample consists of the 2 channels (left and right) of 16-bit signed little-endian integers.
+// For example, the duration of a stream of n bytes is n / BytesPerSample / (the sample rate) seconds.
+const BytesPerSample = channelNum * bytesPerSample
+
+// limiterRelease is the factor by which the gain reduction of the limiter decays per frame.
+// The reduction halves in about 1400 frames (about 30 [ms] at 44100 [Hz]).
+const limiterRelease = 0.9995
+
+// limit writes the mixed samples xs to b as 16-bit little endian integers with the limiter applied.
+//
+// When a mixed frame would exceed the int16 range, the limiter lowers the gain just enough at once,
+// and then restores the gain gradually. The gain is carried over between Read calls, so that the gain of
+// a loud mix doesn't jump at the boundaries of the buffers.
+// While the gain is not reduced, the samples are kept as they are, e.g. for a single player at full volume.
+//
+// limit must be called with the lock.
+func (p *players) limit(b []byte, xs []int) {
+	for i := 0; i < len(xs); i += channelNum {
+		frame := xs[i : i+channelNum]
+		gain := 1 - p.limiterReduction
+		for _, x := range frame {
+			switch {
+			case float64(x)*gain > math.MaxInt16:
+				gain = math.MaxInt16 / float64(x)
+			case float64(x)*gain < math.MinInt16:
+				gain = math.MinInt16 / float64(x)
+			}
+		}
+		p.limiterReduction = 1 - gain
+		for j, x := range frame {
+			y := x
+			if gain < 1 {
+				y = int(math.Floor(float64(x)*gain + 0.5))
+			}
+			if y > math.MaxInt16 {
+				y = math.MaxInt16
+			}
+			if y < math.MinInt16 {
+				y = math.MinInt16
+			}
+			b[2*(i+j)] = byte(y)
+			b[2*(i+j)+1] = byte(y >> 8)
+		}
+		p.limiterReduction *= limiterRelease
+		// Stop reducing the gain when the reduction no longer affects the samples.
+		if p.limiterReduction < 1.0/(1<<17) {
+			p.limiterReduction = 0
+		}
 	}
-	return b
 }
 
 func (p *players) Read(b []byte) (int, error) {
@@ -64,6 +131,7 @@ func (p *players) Read(b []byte) (int, error) {
 	defer p.Unlock()
 
 	if len(p.players) == 0 {
+		p.limiterReduction = 0
 		l := len(b)
 		l &= mask
 		copy(b, make([]byte, l))
@@ -71,51 +139,85 @@ func (p *players) Read(b []byte) (int, error) {
 	}
 	closed := []*Player{}
 	l := len(b)
+	active := []*Player{}
 	for player := range p.players {
-		if _, ok := p.seekings[player]; ok {
-			continue
-		}
-		if err := player.readToBuffer(l); err == io.EOF {
+		active = append(active, player)
+	}
+	errs := p.readToBuffers(active, l)
+	for i, player := range active {
+		if err := errs[i]; err == io.EOF {
 			closed = append(closed, player)
 		} else if err != nil {
 			return 0, err
 		}
-		l = min(player.bufferLength(), l)
 	}
+	// A player whose buffer is shorter than l, e.g. because its decoder is not ready yet, is mixed
+	// with silence for the missing part so that the other players and the output are not stalled.
 	l &= mask
 	b16s := [][]int16{}
 	for player := range p.players {
-		if _, ok := p.seekings[player]; ok {
-			continue
-		}
 		b16s = append(b16s, player.bufferToInt16(l))
 	}
-	for i := 0; i < l/2; i++ {
+	xs := make([]int, l/2)
+	for i := range xs {
 		x := 0
 		for _, b16 := range b16s {
 			x += int(b16[i])
 		}
-		if x > (1<<15)-1 {
-			x = (1 << 15) - 1
-		}
-		if x < -(1 << 15) {
-			x = -(1 << 15)
-		}
-		b[2*i] = byte(x)
-		b[2*i+1] = byte(x >> 8)
+		xs[i] = x
 	}
+	p.limit(b[:l], xs)
 	for player := range p.players {
-		if _, ok := p.seekings[player]; ok {
-			continue
-		}
 		player.proceed(l)
 	}
 	for _, pl := range closed {
+		pl.finished = true
 		delete(p.players, pl)
 	}
 	return l, nil
 }
 
+// readToBuffers reads the sources of the players with at most maxDecodeWorkers goroutines,
+// and returns the errors for each player.
+//
+// readToBuffers must be called with the lock.
+func (p *players) readToBuffers(players []*Player, length int) []error {
+	errs := make([]error, len(players))
+	if p.maxDecodeWorkers <= 1 || len(players) <= 1 {
+		for i, player := range players {
+			errs[i] = player.readToBuffer(length)
+		}
+		return errs
+	}
+	sem := make(chan struct{}, p.maxDecodeWorkers)
+	var wg sync.WaitGroup
+	for i, player := range players {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, player *Player) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = player.readToBuffer(length)
+		}(i, player)
+	}
+	wg.Wait()
+	return errs
+}
+
+func (p *players) setMaxDecodeWorkers(n int) {
+	p.Lock()
+	defer p.Unlock()
+	p.maxDecodeWorkers = n
+}
+
+func (p *players) getMaxDecodeWorkers() int {
+	p.RLock()
+	defer p.RUnlock()
+	return p.maxDecodeWorkers
+}
+
 func (p *players) addPlayer(player *Player) {
 	p.Lock()
 	defer p.Unlock()
@@ -126,18 +228,27 @@ func (p *players) removePlayer(player *Player) {
 	p.Lock()
 	defer p.Unlock()
 	delete(p.players, player)
+	delete(p.paused, player)
 }
 
-func (p *players) addSeeking(player *Player) {
+// pauseAll moves all the playing players to the paused players.
+func (p *players) pauseAll() {
 	p.Lock()
 	defer p.Unlock()
-	p.seekings[player] = struct{}{}
+	for player := range p.players {
+		p.paused[player] = struct{}{}
+	}
+	p.players = map[*Player]struct{}{}
 }
 
-func (p *players) removeSeeking(player *Player) {
+// resumeAll moves all the paused players to the playing players.
+func (p *players) resumeAll() {
 	p.Lock()
 	defer p.Unlock()
-	delete(p.seekings, player)
+	for player := range p.paused {
+		p.players[player] = struct{}{}
+	}
+	p.paused = map[*Player]struct{}{}
 }
 
 func (p *players) hasPlayer(player *Player) bool {
@@ -147,17 +258,94 @@ func (p *players) hasPlayer(player *Player) bool {
 	return ok
 }
 
+// markWritten records the positions of the playing players at the time when
+// the mixed stream is written to the driver.
+func (p *players) markWritten(now time.Time) {
+	p.Lock()
+	defer p.Unlock()
+	for player := range p.players {
+		player.writtenPos = player.pos
+		player.writtenTime = now
+	}
+}
+
+// fireBeatCallbacks invokes the beat callbacks of the playing players for all the beats
+// that have been heard since the last call.
+func (p *players) fireBeatCallbacks() {
+	p.RLock()
+	players := []*Player{}
+	for player := range p.players {
+		if player.beat != nil {
+			players = append(players, player)
+		}
+	}
+	p.RUnlock()
+
+	for _, player := range players {
+		pos := player.PreciseCurrent()
+		p.Lock()
+		b := player.beat
+		var beats []int
+		if b != nil {
+			beats = b.beatsUntil(pos)
+		}
+		p.Unlock()
+		// Call the callback without the lock so that the callback can call the player's functions.
+		for _, beat := range beats {
+			b.f(beat)
+		}
+	}
+}
+
+func (p *players) writtenPosition(player *Player) (pos int64, t time.Time, latency time.Duration, ok bool) {
+	p.RLock()
+	defer p.RUnlock()
+	if _, ok := p.players[player]; !ok {
+		return 0, time.Time{}, 0, false
+	}
+	if player.writtenTime.IsZero() {
+		return 0, time.Time{}, 0, false
+	}
+	return player.writtenPos, player.writtenTime, p.latency, true
+}
+
+func (p *players) setLatency(latency time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+	p.latency = latency
+}
+
+func (p *players) getLatency() time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+	return p.latency
+}
+
+func (p *players) isFinished(player *Player) bool {
+	p.RLock()
+	defer p.RUnlock()
+	return player.finished
+}
+
 func (p *players) hasSource(src ReadSeekCloser) bool {
 	p.RLock()
 	defer p.RUnlock()
 	for player := range p.players {
-		if player.src == src {
+		if underlyingSource(player.src) == underlyingSource(src) {
 			return true
 		}
 	}
 	return false
 }
 
+// underlyingSource returns the source that src wraps if src is created by NewPlayerFromReadSeeker.
+func underlyingSource(src ReadSeekCloser) io.Reader {
+	if r, ok := src.(*readSeekCloser); ok {
+		return r.ReadSeeker
+	}
+	return src
+}
+
 // A Context is a current state of audio.
 //
 // There should be at most one Context object.
@@ -192,6 +380,14 @@ type Context struct {
 	sampleRate   int
 	frames       int64
 	writtenBytes int64
+	strict       bool
+
+	// blurred represents whether the players are paused by losing the window focus.
+	// blurred is accessed only from the focus hook.
+	blurred bool
+
+	// null represents whether the context is a null context without an audio device.
+	null bool
 }
 
 var (
@@ -205,6 +401,28 @@ var (
 //
 // NewContext panics when an audio context is already created.
 func NewContext(sampleRate int) (*Context, error) {
+	return newContext(sampleRate, false), nil
+}
+
+// nullContextSampleRate is the sample rate of a null context.
+// This is the most common sample rate of audio files, and then resampling is avoided in most cases.
+const nullContextSampleRate = 44100
+
+// NewNullContext creates a new audio context that doesn't use any audio device.
+//
+// A null context works as same as a regular context: players can be created and played, and
+// their positions proceed by Update. The samples are just discarded instead of being output.
+// This is useful to run the same game code without sound, e.g. on servers, in tests or on CI.
+//
+// The sample rate of a null context is 44100.
+// IsAvailable of a null context returns false.
+//
+// NewNullContext panics when an audio context is already created.
+func NewNullContext() *Context {
+	return newContext(nullContextSampleRate, true)
+}
+
+func newContext(sampleRate int, null bool) *Context {
 	theContextLock.Lock()
 	defer theContextLock.Unlock()
 	if theContext != nil {
@@ -212,14 +430,49 @@ func NewContext(sampleRate int) (*Context, error) {
 	}
 	c := &Context{
 		sampleRate: sampleRate,
+		null:       null,
 	}
 	theContext = c
 	c.players = &players{
-		players:  map[*Player]struct{}{},
-		seekings: map[*Player]struct{}{},
+		players: map[*Player]struct{}{},
+		paused:  map[*Player]struct{}{},
+		latency: defaultOutputLatency,
+
+		maxDecodeWorkers: defaultMaxDecodeWorkers(),
 	}
-	return c, nil
+	ui.AddFocusHook(c.onFocusChanged)
+	return c
+}
 
+// IsAvailable returns a boolean value indicating whether the context outputs audio to a device.
+//
+// IsAvailable returns false for a null context created by NewNullContext, and true otherwise.
+//
+// This function is concurrent-safe.
+func (c *Context) IsAvailable() bool {
+	return !c.null
+}
+
+// NewContextDefault creates a new audio context with the native sample rate of the platform's audio output.
+//
+// Using the native sample rate minimizes resampling by the platform, and decoders like audio/vorbis and audio/wav
+// adjust the sources to the rate. Use SampleRate to get the chosen rate.
+//
+// The sample rate is queried from the platform:
+//
+//   - On browsers, AudioContext.sampleRate of the Web Audio API.
+//   - On Android, AudioManager.PROPERTY_OUTPUT_SAMPLE_RATE.
+//   - On iOS, the sample rate of AVAudioSession.
+//   - On macOS, the nominal sample rate of the default output device of Core Audio.
+//
+// On Windows and Linux, the audio driver doesn't provide a way to query the output device, and the sample rate is 48000.
+// The sample rate is also 48000 when querying fails, e.g. when NewContextDefault is called on Android before the JVM is available.
+//
+// Use NewContext instead when you need a specific sample rate.
+//
+// NewContextDefault panics when an audio context is already created.
+func NewContextDefault() (*Context, error) {
+	return NewContext(nativeSampleRate())
 }
 
 // Update proceeds the inner (logical) time of the context by 1/60 second.
@@ -237,7 +490,7 @@ func (c *Context) Update() error {
 	// but if Ebiten is used for a shared library, the timing when init functions are called
 	// is unexpectable.
 	// e.g. a variable for JVM on Android might not be set.
-	if c.driver == nil {
+	if c.driver == nil && !c.null {
 		// TODO: Rename this other than player
 		p, err := oto.NewPlayer(c.sampleRate, channelNum, bytesPerSample)
 		c.driver = p
@@ -255,6 +508,11 @@ func (c *Context) Update() error {
 	if err != nil {
 		return err
 	}
+	if c.null {
+		c.players.markWritten(time.Now())
+		c.players.fireBeatCallbacks()
+		return nil
+	}
 	if n != len(buf) {
 		return c.driver.Close()
 	}
@@ -265,15 +523,173 @@ func (c *Context) Update() error {
 	if err != nil {
 		return err
 	}
+	c.players.markWritten(time.Now())
+	c.players.fireBeatCallbacks()
 	return nil
 }
 
+// defaultOutputLatency is the default estimated latency between writing to the driver and hearing.
+// The data written at an Update is played after the data written at the previous Update.
+const defaultOutputLatency = time.Second / ebiten.FPS
+
+// SetOutputLatency sets the estimated latency between when the audio data is written to the device and
+// when the data is actually heard.
+//
+// The latency is used to compensate Player.PreciseCurrent.
+// The actual latency depends on the environment (e.g. the OS's audio buffer and the audio device),
+// and it is recommended to let users calibrate this value e.g. in a rhythm game.
+// The default value is 1/60 second.
+func (c *Context) SetOutputLatency(latency time.Duration) {
+	c.players.setLatency(latency)
+}
+
+// OutputLatency returns the estimated output latency set by SetOutputLatency.
+func (c *Context) OutputLatency() time.Duration {
+	return c.players.getLatency()
+}
+
+// SetMaxDecodeWorkers sets the maximum number of goroutines to read the players' sources concurrently.
+//
+// At each Update, the context reads the sources of all the playing players. Reading a source
+// can be a heavy task e.g. decoding Ogg/Vorbis lazily, and the sources are read concurrently to
+// make use of multiple processors. n limits the concurrency not to oversubscribe the CPU
+// especially on devices with few cores. n = 1 means all the sources are read sequentially.
+//
+// The default value is the half of runtime.GOMAXPROCS(0), or 1 if it is less than 1.
+//
+// If n is less than 1, SetMaxDecodeWorkers panics.
+//
+// This function is concurrent-safe.
+func (c *Context) SetMaxDecodeWorkers(n int) {
+	if n < 1 {
+		panic("audio: n must be equal to or more than 1")
+	}
+	c.players.setMaxDecodeWorkers(n)
+}
+
+// MaxDecodeWorkers returns the maximum number of goroutines set by SetMaxDecodeWorkers.
+//
+// This function is concurrent-safe.
+func (c *Context) MaxDecodeWorkers() int {
+	return c.players.getMaxDecodeWorkers()
+}
+
+// PauseAll pauses all the playing players.
+// The paused players can be resumed by ResumeAll.
+//
+// This function is concurrent-safe.
+func (c *Context) PauseAll() {
+	c.players.pauseAll()
+}
+
+// ResumeAll resumes all the players paused by PauseAll.
+//
+// Players paused or closed explicitly after PauseAll are not resumed.
+//
+// This function is concurrent-safe.
+func (c *Context) ResumeAll() {
+	c.players.resumeAll()
+}
+
+// onFocusChanged pauses all the players when the window loses focus if ebiten.IsPauseAudioOnBlur is true,
+// and resumes them when the window regains focus.
+//
+// onFocusChanged is called from the UI as soon as the focus changes, even while the game's update function
+// (and then Update) is not called, e.g. on desktops and browsers where the game loop stops while the window is not focused.
+func (c *Context) onFocusChanged(focused bool) {
+	if !focused {
+		if c.blurred || !ebiten.IsPauseAudioOnBlur() {
+			return
+		}
+		c.blurred = true
+		c.PauseAll()
+		return
+	}
+	if !c.blurred {
+		return
+	}
+	c.blurred = false
+	c.ResumeAll()
+}
+
 // SampleRate returns the sample rate.
 // All audio source must have the same sample rate.
+//
+// Use SampleRate for calculations depending on the sample rate, like converting byte lengths to durations,
+// instead of hardcoding the rate passed to NewContext. This is the only authoritative value especially when
+// the context is created by NewContextDefault.
+//
+// This function is concurrent-safe.
 func (c *Context) SampleRate() int {
 	return c.sampleRate
 }
 
+// AudioFormat represents the format of the audio data that the context mixes and outputs.
+type AudioFormat struct {
+	// SampleRate is the number of samples per second per channel.
+	SampleRate int
+
+	// ChannelNum is the number of channels. The first channel is left and the second is right.
+	ChannelNum int
+
+	// BitsPerSample is the bit depth of a sample of one channel.
+	BitsPerSample int
+
+	// Signed indicates whether a sample is a signed integer.
+	Signed bool
+
+	// LittleEndian indicates whether the byte order of a sample is little endian.
+	LittleEndian bool
+
+	// Interleaved indicates whether the samples of the channels are interleaved (e.g. L, R, L, R, ...).
+	Interleaved bool
+}
+
+// Format returns the format of the audio data that the context mixes and outputs.
+//
+// This is also the format of the streams that players accept (see NewPlayer).
+// The format is the contract for external processing of the mixer's output, and the same values are
+// kept regardless of the internal implementation.
+//
+// This function is concurrent-safe.
+func (c *Context) Format() AudioFormat {
+	return AudioFormat{
+		SampleRate:    c.sampleRate,
+		ChannelNum:    channelNum,
+		BitsPerSample: bytesPerSample * 8,
+		Signed:        true,
+		LittleEndian:  true,
+		Interleaved:   true,
+	}
+}
+
+// SetStrictSampleRate enables or disables the strict mode of sample rates.
+//
+// By default, the strict mode is disabled and decoders like audio/vorbis and audio/wav
+// resample the source silently when its sample rate is different from the context's.
+// Resampling degrades the audio quality and costs CPU time.
+// When the strict mode is enabled, such decoders return an error instead
+// so that the mismatch can be found during development.
+func (c *Context) SetStrictSampleRate(strict bool) {
+	c.strict = strict
+}
+
+// IsStrictSampleRate returns a boolean value indicating whether the strict mode of sample rates is enabled.
+func (c *Context) IsStrictSampleRate() bool {
+	return c.strict
+}
+
+// CheckSampleRate returns an error when the strict mode of sample rates is enabled and
+// the given sample rate of a source is different from the context's.
+//
+// Decoders should call CheckSampleRate before resampling.
+func (c *Context) CheckSampleRate(sampleRate int) error {
+	if c.strict && sampleRate != c.sampleRate {
+		return fmt.Errorf("audio: sample rate of the source must be %d but was %d", c.sampleRate, sampleRate)
+	}
+	return nil
+}
+
 // ReadSeekCloser is an io.ReadSeeker and io.Closer.
 type ReadSeekCloser interface {
 	io.ReadSeeker
@@ -297,7 +713,13 @@ func (b *bytesReadSeekCloser) Close() error {
 	return nil
 }
 
+func (b *bytesReadSeekCloser) Size() int64 {
+	return b.reader.Size()
+}
+
 // BytesReadSeekCloser creates ReadSeekCloser from bytes.
+//
+// The bytes are not copied. Each ReadSeekCloser created from the same bytes has an independent read position.
 func BytesReadSeekCloser(b []uint8) ReadSeekCloser {
 	return &bytesReadSeekCloser{bytes.NewReader(b)}
 }
@@ -310,6 +732,56 @@ type Player struct {
 	sampleRate int
 	pos        int64
 	volume     float64
+
+	// volumeTarget, volumeStep and volumeRampFrames are the state of the volume ramp by SetVolumeSmooth.
+	// volume and these are protected by the players' lock.
+	volumeTarget     float64
+	volumeStep       float64
+	volumeRampFrames int
+
+	// pan is the stereo position in [-1, 1]. This is protected by the players' lock.
+	pan float64
+
+	// lowPass and highPass are protected by the players' lock.
+	lowPass  onePoleFilter
+	highPass onePoleFilter
+
+	// finished is true when the stream reached EOF. This is protected by the players' lock.
+	finished     bool
+	rewindOnPlay bool
+
+	// writtenPos and writtenTime are the position and the time when the mixed stream was last written to the driver.
+	// These are protected by the players' lock.
+	writtenPos  int64
+	writtenTime time.Time
+
+	// beat is the callback set by SetBeatCallback. This is protected by the players' lock.
+	beat *beatCallback
+
+	// loop, loopStart and loopEnd are the loop settings in bytes. loopEnd = 0 means the end of the source.
+	// These are protected by the players' lock.
+	loop      bool
+	loopStart int64
+	loopEnd   int64
+
+	// srcPos is the position of the source to read next.
+	srcPos int64
+
+	// seeking indicates whether seeking the source to seekTo is pending.
+	// Seeking is done at the next reading. These are protected by the players' lock.
+	seeking bool
+	seekTo  int64
+
+	// srcEnd is the size of the source detected by reaching EOF. 0 means unknown.
+	srcEnd int64
+}
+
+// ErrNotSeekable is returned by Player.Seek when the source is not seekable.
+var ErrNotSeekable = errors.New("audio: the source is not seekable")
+
+// canSeeker is implemented by sources that can report whether seeking is supported.
+type canSeeker interface {
+	CanSeek() bool
 }
 
 // NewPlayer creates a new player with the given stream.
@@ -322,7 +794,25 @@ type Player struct {
 //
 // NewPlayer tries to rewind src by calling Seek to get the current position.
 // NewPlayer returns error when the Seek returns error.
+//
+// If src has a method CanSeek() bool and it returns false, src is treated as a non-seekable stream
+// (e.g. a network stream): NewPlayer doesn't call Seek and the player's Seek returns ErrNotSeekable.
 func NewPlayer(context *Context, src ReadSeekCloser) (*Player, error) {
+	return NewPlayerWithVolume(context, src, 1)
+}
+
+// NewPlayerWithVolume creates a new player with the given stream and the initial volume.
+//
+// Unlike calling SetVolume after NewPlayer, the player never outputs any samples at the default volume.
+//
+// volume must be in between 0 and 1. This function panics otherwise.
+//
+// NewPlayerWithVolume returns error in the same situation of NewPlayer.
+func NewPlayerWithVolume(context *Context, src ReadSeekCloser, volume float64) (*Player, error) {
+	// The condition must be true when volume is NaN.
+	if !(0 <= volume && volume <= 1) {
+		panic("audio: volume must be in between 0 and 1")
+	}
 	if context.players.hasSource(src) {
 		return nil, errors.New("audio: src cannot be shared with another Player")
 	}
@@ -331,23 +821,75 @@ func NewPlayer(context *Context, src ReadSeekCloser) (*Player, error) {
 		src:        src,
 		sampleRate: context.sampleRate,
 		buf:        []byte{},
-		volume:     1,
+		volume:     volume,
+		highPass:   onePoleFilter{highPass: true},
 	}
-	// Get the current position of the source.
-	pos, err := p.src.Seek(0, io.SeekCurrent)
-	if err != nil {
-		return nil, err
+	if p.CanSeek() {
+		// Get the current position of the source.
+		pos, err := p.src.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		p.pos = pos
+		p.srcPos = pos
 	}
-	p.pos = pos
 	runtime.SetFinalizer(p, (*Player).Close)
 	return p, nil
 }
 
+// NewPlayerFromReadSeeker creates a new player with the given stream.
+//
+// The player pulls the data from src on demand as the context mixes the players,
+// and the data is never read into memory at once.
+// This is suitable for long music decoded lazily e.g. by vorbis.DecodeStream.
+//
+// If src implements io.Closer, closing the player closes src.
+// If src has a method Size() int64, Player.Length works as well as a player created by NewPlayer.
+//
+// The other behaviors are same as NewPlayer.
+func NewPlayerFromReadSeeker(context *Context, src io.ReadSeeker) (*Player, error) {
+	return NewPlayer(context, &readSeekCloser{src})
+}
+
+// readSeekCloser is a ReadSeekCloser wrapping an io.ReadSeeker.
+type readSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (r *readSeekCloser) Close() error {
+	if c, ok := r.ReadSeeker.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (r *readSeekCloser) CanSeek() bool {
+	if c, ok := r.ReadSeeker.(canSeeker); ok {
+		return c.CanSeek()
+	}
+	return true
+}
+
+// Size returns the size of the source, or -1 if the size is unknown.
+func (r *readSeekCloser) Size() int64 {
+	if s, ok := r.ReadSeeker.(sizer); ok {
+		return s.Size()
+	}
+	return -1
+}
+
 // NewPlayerFromBytes creates a new player with the given bytes.
 //
 // As opposed to NewPlayer, you don't have to care if src is already used by another player or not.
 // src can be shared by multiple players.
 //
+// The bytes are not copied: all the players created from the same src refer to the same underlying memory,
+// and each player has its own independent read position.
+// This is memory-efficient to play the same sound effect simultaneously by many players,
+// e.g. decode a sound once (e.g. by ioutil.ReadAll on a stream from audio/wav or audio/vorbis)
+// and create a player from the decoded bytes every time the sound is played.
+// src must not be modified while the players are used.
+//
 // The format of src should be same as noted at NewPlayer.
 //
 // NewPlayerFromBytes returns error in the same situation of NewPlayer.
@@ -368,40 +910,139 @@ func (p *Player) Close() error {
 }
 
 func (p *Player) readToBuffer(length int) error {
-	bb := make([]byte, length)
-	n, err := p.src.Read(bb)
-	if 0 < n {
-		p.buf = append(p.buf, bb[:n]...)
+	if p.seeking {
+		p.seeking = false
+		pos, err := p.src.Seek(p.seekTo, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		p.srcPos = pos
+	}
+	// Read only the data that is not buffered yet, e.g. after a short read by a slow decoder.
+	// A looping player continues reading from the loop start until the buffer is filled.
+	rewound := false
+	for len(p.buf) < length {
+		bb := make([]byte, length-len(p.buf))
+		if p.loop && p.loopEnd > 0 {
+			if rest := p.loopEnd - p.srcPos; rest < int64(len(bb)) {
+				if rest < 0 {
+					rest = 0
+				}
+				bb = bb[:rest]
+			}
+		}
+		// A decoder might return less data than requested at once. Read repeatedly to fill the buffer
+		// so that the mixing is not limited by short reads.
+		n := 0
+		var err error
+		for n < len(bb) && err == nil {
+			var m int
+			m, err = p.src.Read(bb[n:])
+			if m == 0 && err == nil {
+				// No data is available for now.
+				break
+			}
+			n += m
+		}
+		if 0 < n {
+			p.buf = append(p.buf, bb[:n]...)
+		}
+		p.srcPos += int64(n)
+		if !p.loop || !p.CanSeek() {
+			return err
+		}
+		if err != io.EOF && (p.loopEnd == 0 || p.srcPos < p.loopEnd) {
+			return err
+		}
+		if n == 0 && rewound {
+			// The loop range is empty.
+			return nil
+		}
+		if p.loopEnd == 0 {
+			p.srcEnd = p.srcPos
+		}
+		// Rewind the source here so that the next data continues without any gap.
+		pos, err := p.src.Seek(p.loopStart, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		p.srcPos = pos
+		rewound = true
 	}
-	return err
+	return nil
 }
 
 func (p *Player) bufferToInt16(lengthInBytes int) []int16 {
 	r := make([]int16, lengthInBytes/2)
 	for i := 0; i < lengthInBytes/2; i++ {
-		r[i] = int16(p.buf[2*i]) | (int16(p.buf[2*i+1]) << 8)
-		r[i] = int16(float64(r[i]) * p.volume)
+		if i%channelNum == 0 && p.volumeRampFrames > 0 {
+			p.volumeRampFrames--
+			if p.volumeRampFrames == 0 {
+				p.volume = p.volumeTarget
+			} else {
+				p.volume += p.volumeStep
+			}
+		}
+		// The data that is not buffered yet is treated as silence.
+		x := 0.0
+		if 2*i+1 < len(p.buf) {
+			x = float64(int16(p.buf[2*i]) | (int16(p.buf[2*i+1]) << 8))
+		}
+		x *= p.volume * p.panGain(i%channelNum)
+		x = p.lowPass.apply(i, x)
+		x = p.highPass.apply(i, x)
+		r[i] = int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, x)))
 	}
 	return r
 }
 
+// proceed consumes the buffered data of the given length.
+//
+// If the buffered data is shorter than length, the position proceeds only by the buffered data.
 func (p *Player) proceed(length int) {
+	if len(p.buf) < length {
+		length = len(p.buf)
+	}
 	p.buf = p.buf[length:]
 	p.pos += int64(length)
-}
-
-func (p *Player) bufferLength() int {
-	return len(p.buf)
+	if !p.loop {
+		return
+	}
+	// The buffered data jumps from the loop end to the loop start.
+	end := p.loopEnd
+	if end == 0 {
+		end = p.srcEnd
+	}
+	if end <= p.loopStart || p.pos < end {
+		return
+	}
+	p.pos = p.loopStart + (p.pos-end)%(end-p.loopStart)
 }
 
 // Play plays the stream.
 //
-// Play always returns nil.
+// If the player already finished playing the stream and SetRewindOnPlay(true) is called,
+// Play rewinds the player and plays the stream from the start.
+//
+// Play returns error when rewinding returns error. Otherwise, Play always returns nil.
 func (p *Player) Play() error {
+	if p.rewindOnPlay && p.players.isFinished(p) {
+		if err := p.Rewind(); err != nil {
+			return err
+		}
+	}
 	p.players.addPlayer(p)
 	return nil
 }
 
+// SetRewindOnPlay sets whether Play rewinds the player when the player already finished playing.
+//
+// This is useful e.g. to play a sound effect again with the same player.
+// The default value is false, and then Play does nothing effectively for a finished player.
+func (p *Player) SetRewindOnPlay(rewind bool) {
+	p.rewindOnPlay = rewind
+}
+
 // IsPlaying returns boolean indicating whether the player is playing.
 func (p *Player) IsPlaying() bool {
 	return p.players.hasPlayer(p)
@@ -409,28 +1050,104 @@ func (p *Player) IsPlaying() bool {
 
 // Rewind rewinds the current position to the start.
 //
-// Rewind returns error when seeking the source returns error.
+// Rewind returns error in the same situation of Seek.
 func (p *Player) Rewind() error {
 	return p.Seek(0)
 }
 
+// CanSeek returns a boolean value indicating whether the player's source supports seeking.
+//
+// CanSeek returns false only when the source has a method CanSeek() bool and it returns false.
+// This is useful e.g. to hide a seek bar for a live stream.
+func (p *Player) CanSeek() bool {
+	if s, ok := p.src.(canSeeker); ok {
+		return s.CanSeek()
+	}
+	return true
+}
+
 // Seek seeks the position with the given offset.
 //
-// Seek returns error when seeking the source returns error.
+// Seek doesn't seek the source immediately: the source is sought when the player's data is read
+// at the next Context.Update. Then, Seek returns quickly without waiting for IO or decoding, and
+// Seek can be called at any time e.g. in the game's update function.
+// Current reflects the new position right after Seek returns.
+//
+// Seek returns ErrNotSeekable when the source is not seekable (see CanSeek).
+// An error of seeking the source is returned by Context.Update.
+//
+// This function is concurrent-safe.
 func (p *Player) Seek(offset time.Duration) error {
-	p.players.addSeeking(p)
-	defer p.players.removeSeeking(p)
-	o := int64(offset) * bytesPerSample * channelNum * int64(p.sampleRate) / int64(time.Second)
-	o &= mask
+	if !p.CanSeek() {
+		return ErrNotSeekable
+	}
+	o := p.durationToBytes(offset)
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.seeking = true
+	p.seekTo = o
 	p.buf = []byte{}
-	pos, err := p.src.Seek(o, io.SeekStart)
-	if err != nil {
-		return err
+	p.pos = o
+	p.finished = false
+	p.writtenPos = 0
+	p.writtenTime = time.Time{}
+	if p.beat != nil {
+		p.beat.resync(p.bytesToDuration(o))
 	}
-	p.pos = pos
 	return nil
 }
 
+// SetLoop sets whether the player loops the stream.
+//
+// When loop is true, the player rewinds the stream to the loop start (see SetLoopRange) when the stream
+// reaches its end, and continues playing. The stream is rewound while the audio data is read, so
+// there is no gap at the loop point unlike calling Rewind and Play after the player finishes.
+//
+// Looping requires a seekable source (see CanSeek). A non-seekable source is not looped and the player
+// finishes at the end of the stream.
+//
+// The default value is false.
+//
+// This function is concurrent-safe.
+func (p *Player) SetLoop(loop bool) {
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.loop = loop
+}
+
+// IsLooping returns a boolean value indicating whether the player loops the stream.
+//
+// This function is concurrent-safe.
+func (p *Player) IsLooping() bool {
+	p.players.RLock()
+	defer p.players.RUnlock()
+	return p.loop
+}
+
+// SetLoopRange sets the range of the loop enabled by SetLoop.
+//
+// When the player reaches end, the player continues playing from start.
+// The stream before start is played only once, which is useful e.g. for a track with an intro section.
+// end = 0 means the end of the stream.
+//
+// The default range is from 0 to the end of the stream.
+//
+// If start is negative or end is not 0 and is not more than start, SetLoopRange panics.
+//
+// This function is concurrent-safe.
+func (p *Player) SetLoopRange(start, end time.Duration) {
+	if start < 0 {
+		panic("audio: start must not be negative")
+	}
+	if end != 0 && end <= start {
+		panic("audio: end must be 0 or more than start")
+	}
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.loopStart = p.durationToBytes(start)
+	p.loopEnd = p.durationToBytes(end)
+}
+
 // Pause pauses the playing.
 //
 // Pause always returns nil.
@@ -440,22 +1157,285 @@ func (p *Player) Pause() error {
 }
 
 // Current returns the current position.
+//
+// This function is concurrent-safe.
 func (p *Player) Current() time.Duration {
-	sample := p.pos / bytesPerSample / channelNum
+	p.players.RLock()
+	defer p.players.RUnlock()
+	return p.bytesToDuration(p.pos)
+}
+
+// PreciseCurrent returns the position that is estimated to be actually heard.
+//
+// While Current returns the position of the data read from the source,
+// the read data is heard later because of buffering.
+// PreciseCurrent interpolates the position with the wall clock from the time when the data is written to the device,
+// and subtracts the output latency (see Context.SetOutputLatency).
+// Then, PreciseCurrent is suitable to synchronize visuals with music, e.g. in rhythm games.
+//
+// When the player is not playing, PreciseCurrent returns the same value as Current.
+func (p *Player) PreciseCurrent() time.Duration {
+	pos, t, latency, ok := p.players.writtenPosition(p)
+	if !ok {
+		return p.Current()
+	}
+	current := p.Current()
+	// The data written at once is at most for one frame in sync mode,
+	// so don't proceed more than one frame from the written position.
+	elapsed := time.Since(t)
+	if max := time.Second / ebiten.FPS; elapsed > max {
+		elapsed = max
+	}
+	d := p.bytesToDuration(pos) + elapsed - latency
+	if d > current {
+		d = current
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// SetBeatCallback sets the callback f that is called at each beat of the music.
+//
+// The beats are at offset, offset + 60s/bpm, offset + 2*60s/bpm, and so on, and the argument of f is
+// the index of the beat starting from 0.
+// The beats are determined by the position that is estimated to be actually heard (see PreciseCurrent),
+// not by the game's frame clock, so the callback stays aligned with the music under frame jitter.
+//
+// f is called from Context.Update. Even when the game's frame rate is lower than the beat rate,
+// f is called for every beat in order, and then f might be called multiple times in one Update.
+// The beats skipped by seeking (including rewinding) are not fired.
+//
+// If f is nil, the callback is removed.
+//
+// If bpm is not positive, SetBeatCallback panics.
+func (p *Player) SetBeatCallback(bpm float64, offset time.Duration, f func(beat int)) {
+	if f == nil {
+		p.players.Lock()
+		p.beat = nil
+		p.players.Unlock()
+		return
+	}
+	if !(0 < bpm) {
+		panic("audio: bpm must be positive")
+	}
+	b := &beatCallback{
+		interval: time.Duration(float64(time.Minute) / bpm),
+		offset:   offset,
+		f:        f,
+	}
+	b.resync(p.PreciseCurrent())
+	p.players.Lock()
+	p.beat = b
+	p.players.Unlock()
+}
+
+// beatCallback is a callback called at each beat.
+type beatCallback struct {
+	interval time.Duration
+	offset   time.Duration
+	f        func(beat int)
+
+	// next is the index of the next beat to fire.
+	next int
+
+	// last is the position at the last beatsUntil call.
+	last time.Duration
+}
+
+// resync sets the next beat to the first beat at or after pos without firing the beats before pos.
+func (b *beatCallback) resync(pos time.Duration) {
+	b.last = pos
+	if pos <= b.offset {
+		b.next = 0
+		return
+	}
+	b.next = int((pos - b.offset + b.interval - 1) / b.interval)
+}
+
+// beatsUntil returns the beats at or before pos that are not fired yet, and proceeds the next beat.
+//
+// When pos goes back e.g. by looping, the beats are resynchronized with pos.
+func (b *beatCallback) beatsUntil(pos time.Duration) []int {
+	if pos < b.last {
+		b.resync(pos)
+	}
+	b.last = pos
+	if pos < b.offset {
+		return nil
+	}
+	last := int((pos - b.offset) / b.interval)
+	var beats []int
+	for ; b.next <= last; b.next++ {
+		beats = append(beats, b.next)
+	}
+	return beats
+}
+
+// sizer is implemented by sources that know their size in bytes, e.g. streams decoded by audio/wav and audio/vorbis.
+type sizer interface {
+	Size() int64
+}
+
+// Length returns the total duration of the stream.
+//
+// Length is calculated from the size of the source in bytes, and this requires the source to have
+// a Size() int64 method like the streams decoded by audio/wav and audio/vorbis.
+// Length returns 0 when the size of the source is unknown.
+func (p *Player) Length() time.Duration {
+	s, ok := p.src.(sizer)
+	if !ok {
+		return 0
+	}
+	size := s.Size()
+	if size < 0 {
+		return 0
+	}
+	return p.bytesToDuration(size)
+}
+
+func (p *Player) durationToBytes(d time.Duration) int64 {
+	b := int64(d) * bytesPerSample * channelNum * int64(p.sampleRate) / int64(time.Second)
+	return b & mask
+}
+
+func (p *Player) bytesToDuration(bytes int64) time.Duration {
+	sample := bytes / bytesPerSample / channelNum
 	return time.Duration(sample) * time.Second / time.Duration(p.sampleRate)
 }
 
 // Volume returns the current volume of this player [0-1].
+//
+// While the volume is changing by SetVolumeSmooth, Volume returns the volume at the moment.
 func (p *Player) Volume() float64 {
+	p.players.RLock()
+	defer p.players.RUnlock()
 	return p.volume
 }
 
 // SetVolume sets the volume of this player.
+// The volume is changed immediately. A large change might cause a click noise. Use SetVolumeSmooth to avoid this.
+//
 // volume must be in between 0 and 1. This function panics otherwise.
 func (p *Player) SetVolume(volume float64) {
 	// The condition must be true when volume is NaN.
 	if !(0 <= volume && volume <= 1) {
 		panic("audio: volume must be in between 0 and 1")
 	}
+	p.players.Lock()
+	defer p.players.Unlock()
 	p.volume = volume
+	p.volumeRampFrames = 0
+}
+
+// SetVolumeSmooth changes the volume of this player to volume linearly over the duration d.
+//
+// The volume changes sample by sample as the audio data is mixed, which prevents click (zipper) noises
+// on volume changes. This is also useful for fading in and out e.g. SetVolumeSmooth(0, time.Second).
+// Calling SetVolume or SetVolumeSmooth during the change starts a new change from the volume at the moment.
+//
+// volume must be in between 0 and 1. This function panics otherwise.
+// If d is not positive, the volume is changed immediately like SetVolume.
+func (p *Player) SetVolumeSmooth(volume float64, d time.Duration) {
+	// The condition must be true when volume is NaN.
+	if !(0 <= volume && volume <= 1) {
+		panic("audio: volume must be in between 0 and 1")
+	}
+	frames := int(int64(d) * int64(p.sampleRate) / int64(time.Second))
+	if frames <= 0 {
+		p.SetVolume(volume)
+		return
+	}
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.volumeTarget = volume
+	p.volumeStep = (volume - p.volume) / float64(frames)
+	p.volumeRampFrames = frames
+}
+
+// Pan returns the stereo position of this player in between -1 and 1.
+func (p *Player) Pan() float64 {
+	p.players.RLock()
+	defer p.players.RUnlock()
+	return p.pan
+}
+
+// SetPan sets the stereo position of this player.
+//
+// -1 is full left, 0 is center and 1 is full right. pan out of the range is clamped.
+// If pan is NaN, SetPan panics.
+// Panning attenuates the opposite channel: e.g. at 0.5, the left channel is played at half volume
+// and the right channel is played as it is. The gain of the channel is multiplied with the volume.
+//
+// The default value is 0.
+func (p *Player) SetPan(pan float64) {
+	// The condition must be true when pan is NaN.
+	if !(pan <= 0 || pan > 0) {
+		panic("audio: pan must not be NaN")
+	}
+	pan = math.Max(-1, math.Min(1, pan))
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.pan = pan
+}
+
+// panGain returns the gain of the channel ch (0: left, 1: right) by panning.
+func (p *Player) panGain(ch int) float64 {
+	if ch == 0 {
+		return math.Min(1, 1-p.pan)
+	}
+	return math.Min(1, 1+p.pan)
+}
+
+// LowPassCutoff returns the cutoff frequency [Hz] of the low-pass filter of this player.
+// 0 means the low-pass filter is disabled.
+func (p *Player) LowPassCutoff() float64 {
+	p.players.Lock()
+	defer p.players.Unlock()
+	return p.lowPass.cutoff
+}
+
+// SetLowPassCutoff sets the cutoff frequency [Hz] of the low-pass filter of this player.
+// This is useful for muffled sounds, e.g. underwater or through a wall.
+//
+// The filter is a one-pole filter applied to this player's stream before mixing,
+// after the volume is applied. The CPU cost is negligible per player.
+//
+// hz 0 disables the filter, which is the default.
+// hz must not be negative. This function panics otherwise.
+func (p *Player) SetLowPassCutoff(hz float64) {
+	// The condition must be true when hz is NaN.
+	if !(0 <= hz) {
+		panic("audio: hz must not be negative")
+	}
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.lowPass.setCutoff(hz, p.sampleRate)
+}
+
+// HighPassCutoff returns the cutoff frequency [Hz] of the high-pass filter of this player.
+// 0 means the high-pass filter is disabled.
+func (p *Player) HighPassCutoff() float64 {
+	p.players.Lock()
+	defer p.players.Unlock()
+	return p.highPass.cutoff
+}
+
+// SetHighPassCutoff sets the cutoff frequency [Hz] of the high-pass filter of this player.
+// This is useful for thin sounds, e.g. from a radio or a telephone.
+//
+// The filter is a one-pole filter applied to this player's stream before mixing,
+// after the volume and the low-pass filter are applied. The CPU cost is negligible per player.
+//
+// hz 0 disables the filter, which is the default.
+// hz must not be negative. This function panics otherwise.
+func (p *Player) SetHighPassCutoff(hz float64) {
+	// The condition must be true when hz is NaN.
+	if !(0 <= hz) {
+		panic("audio: hz must not be negative")
+	}
+	p.players.Lock()
+	defer p.players.Unlock()
+	p.highPass.setCutoff(hz, p.sampleRate)
 }
@@ -0,0 +1,647 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+func TestPlayersReadSinglePlayerFullScale(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	vs := []int16{1<<15 - 1, -(1 << 15), 30000, -30000, 24577, 0, 1, -1}
+	src := make([]byte, 2*len(vs))
+	for i, v := range vs {
+		src[2*i] = byte(v)
+		src[2*i+1] = byte(v >> 8)
+	}
+	p := &Player{
+		players: ps,
+		src:     BytesReadSeekCloser(src),
+		buf:     []byte{},
+		volume:  1,
+	}
+	ps.players[p] = struct{}{}
+	b := make([]byte, len(src))
+	if _, err := io.ReadFull(ps, b); err != nil {
+		t.Fatal(err)
+		return
+	}
+	// The samples of a single player never clip and must pass through unchanged.
+	if !bytes.Equal(b, src) {
+		t.Errorf("b: got %v, want: %v", b, src)
+	}
+}
+
+func TestPlayersReadLimiterAcrossBuffers(t *testing.T) {
+	const (
+		quiet = 1000
+		loud  = 30000
+	)
+	// Each buffer has 8 frames. The first buffer is quiet, and the second buffer gets loud
+	// in the middle, where the mix of the two players exceeds the int16 range.
+	vs := []int16{}
+	for i := 0; i < 8; i++ {
+		vs = append(vs, quiet, quiet)
+	}
+	for i := 0; i < 8; i++ {
+		v := int16(quiet)
+		if i >= 4 {
+			v = loud
+		}
+		vs = append(vs, v, v)
+	}
+	for i := 0; i < 8; i++ {
+		vs = append(vs, quiet, quiet)
+	}
+	src := make([]byte, 2*len(vs))
+	for i, v := range vs {
+		src[2*i] = byte(v)
+		src[2*i+1] = byte(v >> 8)
+	}
+
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	for i := 0; i < 2; i++ {
+		p := &Player{
+			players: ps,
+			src:     BytesReadSeekCloser(src),
+			buf:     []byte{},
+			volume:  1,
+		}
+		ps.players[p] = struct{}{}
+	}
+
+	const bufSize = 8 * channelNum * bytesPerSample
+	bufs := make([][]int16, 3)
+	for i := range bufs {
+		b := make([]byte, bufSize)
+		if _, err := io.ReadFull(ps, b); err != nil {
+			t.Fatal(err)
+			return
+		}
+		for j := 0; j < len(b)/2; j++ {
+			bufs[i] = append(bufs[i], int16(b[2*j])|int16(b[2*j+1])<<8)
+		}
+	}
+
+	// The samples before the overflow must be the same in the quiet buffer and in the overflowing buffer.
+	for i := 0; i < 4*channelNum; i++ {
+		if got, want := bufs[0][i], int16(2*quiet); got != want {
+			t.Errorf("buffer 0, sample %d: got %d, want: %d", i, got, want)
+		}
+		if got, want := bufs[1][i], int16(2*quiet); got != want {
+			t.Errorf("buffer 1, sample %d: got %d, want: %d", i, got, want)
+		}
+	}
+	for i := 4 * channelNum; i < len(bufs[1]); i++ {
+		if got := bufs[1][i]; got < 2*quiet {
+			t.Errorf("buffer 1, sample %d: got %d, want: >= %d", i, got, 2*quiet)
+		}
+	}
+	// The reduced gain is carried over to the next buffer and restored gradually.
+	prev := int16(0)
+	for i, got := range bufs[2] {
+		if got >= 2*quiet {
+			t.Errorf("buffer 2, sample %d: got %d, want: < %d", i, got, 2*quiet)
+		}
+		if got < prev {
+			t.Errorf("buffer 2, sample %d: got %d, want: >= %d", i, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestPlayersReadMaxAmplitude(t *testing.T) {
+	const sampleNum = 256
+	for _, v := range []int16{1<<15 - 1, -(1 << 15)} {
+		ps := &players{
+			players: map[*Player]struct{}{},
+		}
+		for i := 0; i < 8; i++ {
+			src := make([]byte, sampleNum*channelNum*bytesPerSample)
+			for j := 0; j < len(src)/2; j++ {
+				src[2*j] = byte(v)
+				src[2*j+1] = byte(v >> 8)
+			}
+			p := &Player{
+				players: ps,
+				src:     BytesReadSeekCloser(src),
+				buf:     []byte{},
+				volume:  1,
+			}
+			ps.players[p] = struct{}{}
+		}
+		b := make([]byte, sampleNum*channelNum*bytesPerSample)
+		n, err := ps.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(b) {
+			t.Errorf("n: got %d, want: %d", n, len(b))
+		}
+		for i := 0; i < n/2; i++ {
+			got := int16(b[2*i]) | int16(b[2*i+1])<<8
+			// The sum of the samples must be limited to the int16 range without wrapping around.
+			want := int16(math.MaxInt16)
+			if v < 0 {
+				want = math.MinInt16
+			}
+			if got != want {
+				t.Errorf("sample %d (source: %d): got %d, want: %d", i, v, got, want)
+			}
+		}
+	}
+}
+
+func TestPlayersReadConcurrently(t *testing.T) {
+	const sampleNum = 256
+	for _, workers := range []int{1, 2, 4} {
+		ps := &players{
+			players:          map[*Player]struct{}{},
+			maxDecodeWorkers: workers,
+		}
+		for i := 0; i < 8; i++ {
+			src := make([]byte, sampleNum*channelNum*bytesPerSample)
+			for j := 0; j < len(src)/2; j++ {
+				src[2*j] = 1
+			}
+			p := &Player{
+				players: ps,
+				src:     BytesReadSeekCloser(src),
+				buf:     []byte{},
+				volume:  1,
+			}
+			ps.players[p] = struct{}{}
+		}
+		b := make([]byte, sampleNum*channelNum*bytesPerSample)
+		n, err := ps.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < n/2; i++ {
+			got := int16(b[2*i]) | int16(b[2*i+1])<<8
+			if got != 8 {
+				t.Errorf("workers: %d, sample %d: got %d, want: 8", workers, i, got)
+			}
+		}
+	}
+}
+
+func TestDeinterleave(t *testing.T) {
+	src := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	l, r := Deinterleave(src)
+	if want := []byte{1, 2, 5, 6}; !bytes.Equal(l, want) {
+		t.Errorf("left: got %v, want: %v", l, want)
+	}
+	if want := []byte{3, 4, 7, 8}; !bytes.Equal(r, want) {
+		t.Errorf("right: got %v, want: %v", r, want)
+	}
+	if got := Interleave(l, r); !bytes.Equal(got, src) {
+		t.Errorf("Interleave: got %v, want: %v", got, src)
+	}
+}
+
+func TestNewPlayerFromBytesSharesSource(t *testing.T) {
+	context, err := NewContext(44100)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	src := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	p0, err := NewPlayerFromBytes(context, src)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	p1, err := NewPlayerFromBytes(context, src)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := p0.readToBuffer(4); err != nil {
+		t.Fatal(err)
+		return
+	}
+	// Reading by p0 must not affect the read position of p1.
+	if err := p1.readToBuffer(8); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := p0.buf, src[:4]; !bytes.Equal(got, want) {
+		t.Errorf("p0.buf: got %v, want: %v", got, want)
+	}
+	if got, want := p1.buf, src; !bytes.Equal(got, want) {
+		t.Errorf("p1.buf: got %v, want: %v", got, want)
+	}
+}
+
+type nonSeekableSource struct {
+	ReadSeekCloser
+}
+
+func (n *nonSeekableSource) CanSeek() bool {
+	return false
+}
+
+func TestPlayerSeekNotSeekable(t *testing.T) {
+	p := &Player{
+		src: &nonSeekableSource{BytesReadSeekCloser([]byte{1, 2, 3, 4})},
+	}
+	if p.CanSeek() {
+		t.Errorf("p.CanSeek(): got true, want: false")
+	}
+	if got, want := p.Seek(0), ErrNotSeekable; got != want {
+		t.Errorf("p.Seek(0): got %v, want: %v", got, want)
+	}
+}
+
+func TestOnePoleFilter(t *testing.T) {
+	const sampleRate = 44100
+	const x = 10000
+	lp := &onePoleFilter{}
+	lp.setCutoff(1000, sampleRate)
+	hp := &onePoleFilter{highPass: true}
+	hp.setCutoff(1000, sampleRate)
+	var ylp, yhp float64
+	for i := 0; i < sampleRate*channelNum; i++ {
+		ylp = lp.apply(i, x)
+		yhp = hp.apply(i, x)
+	}
+	// For a constant input, the low-pass filter converges to the input and the high-pass filter converges to 0.
+	if math.Abs(ylp-x) > 1 {
+		t.Errorf("low-pass: got %f, want: %d", ylp, x)
+	}
+	if math.Abs(yhp) > 1 {
+		t.Errorf("high-pass: got %f, want: 0", yhp)
+	}
+
+	disabled := &onePoleFilter{}
+	if got := disabled.apply(0, x); got != x {
+		t.Errorf("disabled: got %f, want: %d", got, x)
+	}
+}
+
+func TestBeatCallbackBeatsUntil(t *testing.T) {
+	b := &beatCallback{
+		interval: 500 * time.Millisecond,
+		offset:   100 * time.Millisecond,
+	}
+	cases := []struct {
+		pos  time.Duration
+		want []int
+	}{
+		{0, nil},
+		{100 * time.Millisecond, []int{0}},
+		{200 * time.Millisecond, nil},
+		// Multiple beats are fired at once when the position proceeds a lot.
+		{1700 * time.Millisecond, []int{1, 2, 3}},
+		{1700 * time.Millisecond, nil},
+		{2100 * time.Millisecond, []int{4}},
+	}
+	for _, c := range cases {
+		got := b.beatsUntil(c.pos)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("b.beatsUntil(%v): got %v, want: %v", c.pos, got, c.want)
+		}
+	}
+
+	b.resync(700 * time.Millisecond)
+	if got, want := b.next, 2; got != want {
+		t.Errorf("b.next after b.resync(700ms): got %d, want: %d", got, want)
+	}
+	b.resync(600 * time.Millisecond)
+	if got, want := b.next, 1; got != want {
+		t.Errorf("b.next after b.resync(600ms): got %d, want: %d", got, want)
+	}
+}
+
+func TestPlayerLoop(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	// 4 frames whose left and right samples are the frame index + 1.
+	src := []byte{1, 0, 1, 0, 2, 0, 2, 0, 3, 0, 3, 0, 4, 0, 4, 0}
+	p := &Player{
+		players: ps,
+		src:     BytesReadSeekCloser(src),
+		buf:     []byte{},
+		volume:  1,
+	}
+	ps.players[p] = struct{}{}
+	p.loop = true
+	// Loop from the second frame.
+	p.loopStart = 4
+
+	b := make([]byte, 10*channelNum*bytesPerSample)
+	if _, err := io.ReadFull(ps, b); err != nil {
+		t.Fatal(err)
+		return
+	}
+	want := []int16{1, 2, 3, 4, 2, 3, 4, 2, 3, 4}
+	for i, w := range want {
+		got := int16(b[4*i]) | int16(b[4*i+1])<<8
+		if got != w {
+			t.Errorf("frame %d: got %d, want: %d", i, got, w)
+		}
+	}
+	if !ps.hasPlayer(p) {
+		t.Errorf("ps.hasPlayer(p): got false, want: true")
+	}
+	// The next frame to play is the second frame.
+	if got, want := p.pos, int64(4); got != want {
+		t.Errorf("p.pos: got %d, want: %d", got, want)
+	}
+}
+
+func TestPlayerLength(t *testing.T) {
+	p := &Player{
+		src:        BytesReadSeekCloser(make([]byte, 2*44100*BytesPerSample)),
+		sampleRate: 44100,
+	}
+	if got, want := p.Length(), 2*time.Second; got != want {
+		t.Errorf("p.Length(): got %v, want: %v", got, want)
+	}
+	p = &Player{
+		src:        &nonSeekableSource{},
+		sampleRate: 44100,
+	}
+	if got, want := p.Length(), time.Duration(0); got != want {
+		t.Errorf("p.Length(): got %v, want: %v", got, want)
+	}
+}
+
+func TestPlayerSeekDeferred(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	src := []byte{1, 0, 1, 0, 2, 0, 2, 0, 3, 0, 3, 0, 4, 0, 4, 0}
+	p := &Player{
+		players:    ps,
+		src:        BytesReadSeekCloser(src),
+		buf:        []byte{},
+		sampleRate: 1,
+		volume:     1,
+	}
+	ps.players[p] = struct{}{}
+
+	// Seek to the third frame. With the sample rate 1, a frame is one second.
+	if err := p.Seek(2 * time.Second); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := p.Current(), 2*time.Second; got != want {
+		t.Errorf("p.Current(): got %v, want: %v", got, want)
+	}
+	b := make([]byte, 2*channelNum*bytesPerSample)
+	if _, err := io.ReadFull(ps, b); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for i, w := range []int16{3, 4} {
+		got := int16(b[4*i]) | int16(b[4*i+1])<<8
+		if got != w {
+			t.Errorf("frame %d: got %d, want: %d", i, got, w)
+		}
+	}
+}
+
+func TestPlayerSetVolumeSmooth(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	p := &Player{
+		players:    ps,
+		buf:        []byte{},
+		sampleRate: 4,
+		volume:     1,
+	}
+	for i := 0; i < 6; i++ {
+		// 1000 for both channels
+		p.buf = append(p.buf, 0xe8, 0x03, 0xe8, 0x03)
+	}
+	// Change the volume in 4 frames.
+	p.SetVolumeSmooth(0, time.Second)
+	r := p.bufferToInt16(len(p.buf))
+	want := []int16{750, 500, 250, 0, 0, 0}
+	for i, w := range want {
+		if got := r[2*i]; got != w {
+			t.Errorf("frame %d (left): got %d, want: %d", i, got, w)
+		}
+		if got := r[2*i+1]; got != w {
+			t.Errorf("frame %d (right): got %d, want: %d", i, got, w)
+		}
+	}
+	if got, want := p.Volume(), 0.0; got != want {
+		t.Errorf("p.Volume(): got %v, want: %v", got, want)
+	}
+}
+
+func TestPlayerSetPan(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	cases := []struct {
+		pan         float64
+		left, right int16
+	}{
+		{0, 1000, 1000},
+		{-1, 1000, 0},
+		{1, 0, 1000},
+		{0.5, 500, 1000},
+		// Out-of-range values are clamped.
+		{-2, 1000, 0},
+	}
+	for _, c := range cases {
+		p := &Player{
+			players: ps,
+			// 1000 for both channels
+			buf:    []byte{0xe8, 0x03, 0xe8, 0x03},
+			volume: 1,
+		}
+		p.SetPan(c.pan)
+		r := p.bufferToInt16(len(p.buf))
+		if r[0] != c.left || r[1] != c.right {
+			t.Errorf("pan: %v: got (%d, %d), want: (%d, %d)", c.pan, r[0], r[1], c.left, c.right)
+		}
+	}
+}
+
+func TestPlayerSetPanNaN(t *testing.T) {
+	p := &Player{
+		players: &players{
+			players: map[*Player]struct{}{},
+		},
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetPan(NaN) must panic")
+		}
+	}()
+	p.SetPan(math.NaN())
+}
+
+// slowSource returns at most one byte per Read like a slow decoder.
+type slowSource struct {
+	io.ReadSeeker
+}
+
+func (s *slowSource) Read(b []byte) (int, error) {
+	if len(b) > 1 {
+		b = b[:1]
+	}
+	return s.ReadSeeker.Read(b)
+}
+
+func TestPlayerReadShortReads(t *testing.T) {
+	src := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	p := &Player{
+		src: &readSeekCloser{&slowSource{bytes.NewReader(src)}},
+		buf: []byte{},
+	}
+	if err := p.readToBuffer(len(src)); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := p.buf, src; !bytes.Equal(got, want) {
+		t.Errorf("p.buf: got %v, want: %v", got, want)
+	}
+	// The size of slowSource is unknown.
+	if got, want := p.Length(), time.Duration(0); got != want {
+		t.Errorf("p.Length(): got %v, want: %v", got, want)
+	}
+}
+
+func TestPlayerPoolFreeVoice(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	pool := &PlayerPool{
+		voices:   make([]*Player, 0, 2),
+		playedAt: make([]int64, 0, 2),
+	}
+	if got, want := pool.freeVoice(), -1; got != want {
+		t.Errorf("pool.freeVoice() for an empty pool: got %d, want: %d", got, want)
+	}
+	for i := 0; i < 2; i++ {
+		v := &Player{players: ps}
+		ps.players[v] = struct{}{}
+		pool.voices = append(pool.voices, v)
+	}
+	pool.playedAt = append(pool.playedAt, 2, 1)
+	// All the voices are playing. The voice played the earliest is reused.
+	if got, want := pool.freeVoice(), 1; got != want {
+		t.Errorf("pool.freeVoice() for a full pool: got %d, want: %d", got, want)
+	}
+	delete(ps.players, pool.voices[0])
+	if got, want := pool.freeVoice(), 0; got != want {
+		t.Errorf("pool.freeVoice() with a stopped voice: got %d, want: %d", got, want)
+	}
+}
+
+func TestContextPauseOnBlur(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+		paused:  map[*Player]struct{}{},
+	}
+	c := &Context{players: ps}
+	p := &Player{players: ps}
+	ps.players[p] = struct{}{}
+
+	c.onFocusChanged(false)
+	if ps.hasPlayer(p) {
+		t.Errorf("ps.hasPlayer(p) after losing focus: got true, want: false")
+	}
+	c.onFocusChanged(true)
+	if !ps.hasPlayer(p) {
+		t.Errorf("ps.hasPlayer(p) after regaining focus: got false, want: true")
+	}
+
+	ebiten.SetPauseAudioOnBlur(false)
+	defer ebiten.SetPauseAudioOnBlur(true)
+	c.onFocusChanged(false)
+	if !ps.hasPlayer(p) {
+		t.Errorf("ps.hasPlayer(p) after losing focus without pausing on blur: got false, want: true")
+	}
+	c.onFocusChanged(true)
+	if !ps.hasPlayer(p) {
+		t.Errorf("ps.hasPlayer(p) after regaining focus without pausing on blur: got false, want: true")
+	}
+}
+
+// starvingSource is a source whose decoder never has data ready.
+type starvingSource struct{}
+
+func (s *starvingSource) Read(b []byte) (int, error) {
+	return 0, nil
+}
+
+func (s *starvingSource) Seek(offset int64, whence int) (int64, error) {
+	return 0, nil
+}
+
+func (s *starvingSource) Close() error {
+	return nil
+}
+
+func TestPlayersReadStarvingPlayer(t *testing.T) {
+	ps := &players{
+		players: map[*Player]struct{}{},
+	}
+	src := []byte{1, 0, 2, 0, 3, 0, 4, 0}
+	p0 := &Player{
+		players: ps,
+		src:     BytesReadSeekCloser(src),
+		buf:     []byte{},
+		volume:  1,
+	}
+	p1 := &Player{
+		players: ps,
+		src:     &starvingSource{},
+		buf:     []byte{},
+		volume:  1,
+	}
+	ps.players[p0] = struct{}{}
+	ps.players[p1] = struct{}{}
+
+	b := make([]byte, len(src))
+	n, err := ps.Read(b)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := n, len(src); got != want {
+		t.Errorf("n: got %d, want: %d", got, want)
+	}
+	if got, want := b, src; !bytes.Equal(got, want) {
+		t.Errorf("b: got %v, want: %v", got, want)
+	}
+	if got, want := p0.pos, int64(len(src)); got != want {
+		t.Errorf("p0.pos: got %d, want: %d", got, want)
+	}
+	// The starving player doesn't proceed.
+	if got, want := p1.pos, int64(0); got != want {
+		t.Errorf("p1.pos: got %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"math"
+)
+
+// onePoleFilter is a one-pole low-pass or high-pass filter for interleaved stereo samples.
+type onePoleFilter struct {
+	highPass bool
+	cutoff   float64
+	alpha    float64
+	prevIn   [channelNum]float64
+	prevOut  [channelNum]float64
+}
+
+func (f *onePoleFilter) setCutoff(cutoff float64, sampleRate int) {
+	if f.cutoff == 0 {
+		// Start from a clean state when the filter is enabled.
+		f.prevIn = [channelNum]float64{}
+		f.prevOut = [channelNum]float64{}
+	}
+	f.cutoff = cutoff
+	if cutoff == 0 {
+		return
+	}
+	rc := 1 / (2 * math.Pi * cutoff)
+	dt := 1 / float64(sampleRate)
+	if f.highPass {
+		f.alpha = rc / (rc + dt)
+	} else {
+		f.alpha = dt / (rc + dt)
+	}
+}
+
+// apply filters the i-th sample x of the interleaved stream.
+func (f *onePoleFilter) apply(i int, x float64) float64 {
+	if f.cutoff == 0 {
+		return x
+	}
+	ch := i % channelNum
+	var y float64
+	if f.highPass {
+		y = f.alpha * (f.prevOut[ch] + x - f.prevIn[ch])
+	} else {
+		y = f.prevOut[ch] + f.alpha*(x-f.prevOut[ch])
+	}
+	f.prevIn[ch] = x
+	f.prevOut[ch] = y
+	return y
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/audio"
+)
+
+// Speaker represents the position of a channel in a multi-channel source.
+type Speaker int
+
+const (
+	SpeakerFrontLeft Speaker = iota
+	SpeakerFrontRight
+	SpeakerFrontCenter
+	SpeakerLFE
+	SpeakerBackLeft
+	SpeakerBackRight
+	SpeakerBackCenter
+	SpeakerSideLeft
+	SpeakerSideRight
+)
+
+// downmixCoefficients returns the gains of the speaker for the left and the right output.
+//
+// The coefficients are the standard ones (ITU-R BS.775): center and surround channels are attenuated by -3dB,
+// and LFE is dropped.
+func downmixCoefficients(s Speaker) (left, right float64) {
+	const c = math.Sqrt2 / 2
+	switch s {
+	case SpeakerFrontLeft:
+		return 1, 0
+	case SpeakerFrontRight:
+		return 0, 1
+	case SpeakerFrontCenter, SpeakerBackCenter:
+		return c, c
+	case SpeakerBackLeft, SpeakerSideLeft:
+		return c, 0
+	case SpeakerBackRight, SpeakerSideRight:
+		return 0, c
+	}
+	return 0, 0
+}
+
+// Downmix converts a multi-channel 8bit or 16bit stream into a 2 channel 16bit stream.
+type Downmix struct {
+	source      audio.ReadSeekCloser
+	eight       bool
+	left, right []float64
+}
+
+// NewDownmix creates a new Downmix.
+//
+// speakers represents the position of each channel in the order of the interleaved source.
+func NewDownmix(source audio.ReadSeekCloser, speakers []Speaker, eight bool) *Downmix {
+	d := &Downmix{
+		source: source,
+		eight:  eight,
+		left:   make([]float64, len(speakers)),
+		right:  make([]float64, len(speakers)),
+	}
+	sumL, sumR := 0.0, 0.0
+	for i, s := range speakers {
+		d.left[i], d.right[i] = downmixCoefficients(s)
+		sumL += d.left[i]
+		sumR += d.right[i]
+	}
+	// Normalize the coefficients to avoid clipping.
+	for i := range speakers {
+		if sumL > 0 {
+			d.left[i] /= sumL
+		}
+		if sumR > 0 {
+			d.right[i] /= sumR
+		}
+	}
+	return d
+}
+
+func (d *Downmix) bytesPerSourceFrame() int {
+	n := len(d.left) * 2
+	if d.eight {
+		n /= 2
+	}
+	return n
+}
+
+func (d *Downmix) Read(b []uint8) (int, error) {
+	srcFrame := d.bytesPerSourceFrame()
+	frames := len(b) / 4
+	buf := make([]uint8, frames*srcFrame)
+	n, err := io.ReadFull(d.source, buf)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	frames = n / srcFrame
+	for i := 0; i < frames; i++ {
+		l, r := 0.0, 0.0
+		for ch := range d.left {
+			var v float64
+			if d.eight {
+				v = float64(int(buf[i*srcFrame+ch])*0x101 - (1 << 15))
+			} else {
+				idx := i*srcFrame + 2*ch
+				v = float64(int16(buf[idx]) | int16(buf[idx+1])<<8)
+			}
+			l += v * d.left[ch]
+			r += v * d.right[ch]
+		}
+		vl, vr := int16(l), int16(r)
+		b[4*i] = uint8(vl)
+		b[4*i+1] = uint8(vl >> 8)
+		b[4*i+2] = uint8(vr)
+		b[4*i+3] = uint8(vr >> 8)
+	}
+	return frames * 4, err
+}
+
+func (d *Downmix) Seek(offset int64, whence int) (int64, error) {
+	srcFrame := int64(d.bytesPerSourceFrame())
+	pos, err := d.source.Seek(offset/4*srcFrame, whence)
+	if err != nil {
+		return 0, err
+	}
+	return pos / srcFrame * 4, nil
+}
+
+func (d *Downmix) Close() error {
+	return d.source.Close()
+}
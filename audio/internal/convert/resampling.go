@@ -28,26 +28,44 @@ func sinc(x float64) float64 {
 	return math.Sin(x) / x
 }
 
+const (
+	// windowSize is the half size of the window of the source frames used to calculate one frame.
+	windowSize = 8
+
+	// readFrames is the number of source frames read at once.
+	readFrames = 1024
+)
+
+// Resampling is a stream converting the sample rate of the source.
+//
+// Resampling keeps only a bounded window of the source frames on memory regardless of the size of the source.
 type Resampling struct {
-	source    audio.ReadSeekCloser
-	size      int64
-	from      int
-	to        int
-	pos       int64
-	srcPos    int64
-	srcCacheL []float64
-	srcCacheR []float64
+	source audio.ReadSeekCloser
+	size   int64
+	from   int
+	to     int
+	pos    int64
+
+	// srcCacheL and srcCacheR are the cached source frames starting at the frame srcCacheStart.
+	// The source is read from the frame srcCacheStart + len(srcCacheL) next.
+	srcCacheL     []float64
+	srcCacheR     []float64
+	srcCacheStart int
+
+	buf []uint8
 }
 
 func NewResampling(source audio.ReadSeekCloser, size int64, from, to int) *Resampling {
+	const cacheSize = 2*windowSize + 1 + readFrames
 	r := &Resampling{
-		source: source,
-		size:   size,
-		from:   from,
-		to:     to,
+		source:    source,
+		size:      size,
+		from:      from,
+		to:        to,
+		srcCacheL: make([]float64, 0, cacheSize),
+		srcCacheR: make([]float64, 0, cacheSize),
+		buf:       make([]uint8, 4*readFrames),
 	}
-	r.srcCacheL = make([]float64, r.size/4)
-	r.srcCacheR = make([]float64, r.size/4)
 	return r
 }
 
@@ -61,31 +79,53 @@ func (r *Resampling) src(i int) (float64, float64, error) {
 	if i < 0 {
 		return 0, 0, nil
 	}
-	if len(r.srcCacheL) <= i {
+	if int(r.size/4) <= i {
 		return 0, 0, nil
 	}
-	pos := int(r.srcPos) / 4
-	if pos <= i {
-		buf := make([]uint8, 4096)
-		n, err := r.source.Read(buf)
-		if err != nil && err != io.EOF {
+	end := r.srcCacheStart + len(r.srcCacheL)
+	if i < r.srcCacheStart || end+readFrames <= i {
+		// The frame is out of the window, e.g. after seeking. Restart reading the source from the frame
+		// with the margin for the window.
+		start := i - 2*windowSize
+		if start < 0 {
+			start = 0
+		}
+		if _, err := r.source.Seek(int64(start)*4, io.SeekStart); err != nil {
 			return 0, 0, err
 		}
-		n = n / 4 * 4
-		buf = buf[:n]
-		for i := 0; i < len(buf)/4; i++ {
-			srcL := float64(int16(buf[4*i])|(int16(buf[4*i+1])<<8)) / (1<<15 - 1)
-			srcR := float64(int16(buf[4*i+2])|(int16(buf[4*i+3])<<8)) / (1<<15 - 1)
-			r.srcCacheL[pos+i] = srcL
-			r.srcCacheR[pos+i] = srcR
+		r.srcCacheL = r.srcCacheL[:0]
+		r.srcCacheR = r.srcCacheR[:0]
+		r.srcCacheStart = start
+	}
+	for r.srcCacheStart+len(r.srcCacheL) <= i {
+		// Drop the frames that are no longer used by the window.
+		if drop := i - 2*windowSize - r.srcCacheStart; drop > 0 {
+			if drop > len(r.srcCacheL) {
+				drop = len(r.srcCacheL)
+			}
+			r.srcCacheL = r.srcCacheL[:copy(r.srcCacheL, r.srcCacheL[drop:])]
+			r.srcCacheR = r.srcCacheR[:copy(r.srcCacheR, r.srcCacheR[drop:])]
+			r.srcCacheStart += drop
+		}
+		n, err := io.ReadFull(r.source, r.buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, 0, err
+		}
+		if n < 4 {
+			// The source is shorter than its size.
+			return 0, 0, nil
+		}
+		for j := 0; j < n/4; j++ {
+			srcL := float64(int16(r.buf[4*j])|(int16(r.buf[4*j+1])<<8)) / (1<<15 - 1)
+			srcR := float64(int16(r.buf[4*j+2])|(int16(r.buf[4*j+3])<<8)) / (1<<15 - 1)
+			r.srcCacheL = append(r.srcCacheL, srcL)
+			r.srcCacheR = append(r.srcCacheR, srcR)
 		}
-		r.srcPos += int64(n)
 	}
-	return r.srcCacheL[i], r.srcCacheR[i], nil
+	return r.srcCacheL[i-r.srcCacheStart], r.srcCacheR[i-r.srcCacheStart], nil
 }
 
 func (r *Resampling) at(t int64) (float64, float64, error) {
-	const windowSize = 8
 	tInSrc := float64(t) * float64(r.from) / float64(r.to)
 	startN := int64(tInSrc) - windowSize
 	if startN < 0 {
@@ -0,0 +1,71 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/audio"
+)
+
+func TestResamplingBoundedWindow(t *testing.T) {
+	const frames = 10000
+	src := make([]uint8, 4*frames)
+	for i := 0; i < frames; i++ {
+		v := int16(i%2000 - 1000)
+		src[4*i] = uint8(v)
+		src[4*i+1] = uint8(v >> 8)
+		src[4*i+2] = uint8(-v)
+		src[4*i+3] = uint8(-v >> 8)
+	}
+	// Resampling to the same rate reproduces the source.
+	r := NewResampling(audio.BytesReadSeekCloser(src), int64(len(src)), 44100, 44100)
+	check := func(b []uint8, offset int) {
+		for i := 0; i < len(b)/4; i++ {
+			got := int16(b[4*i]) | int16(b[4*i+1])<<8
+			want := int16((offset+i)%2000 - 1000)
+			if d := got - want; d < -1 || 1 < d {
+				t.Fatalf("frame %d: got %d, want: %d", offset+i, got, want)
+			}
+		}
+	}
+
+	b := make([]uint8, 4*1000)
+	for i := 0; i < frames/1000; i++ {
+		if _, err := io.ReadFull(r, b); err != nil {
+			t.Fatal(err)
+			return
+		}
+		check(b, 1000*i)
+		if got, max := len(r.srcCacheL), cap(r.srcCacheL); got > max {
+			t.Errorf("len(r.srcCacheL): got %d, want: <= %d", got, max)
+		}
+	}
+	if got, want := cap(r.srcCacheL), 2*windowSize+1+readFrames; got != want {
+		t.Errorf("cap(r.srcCacheL): got %d, want: %d", got, want)
+	}
+
+	// Seeking backward re-reads the source.
+	if _, err := r.Seek(4*3000, io.SeekStart); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+		return
+	}
+	check(b, 3000)
+}
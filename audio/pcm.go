@@ -0,0 +1,84 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// The PCM cache format is:
+//
+//   offset  size  content
+//   0       4     "EPCM"
+//   4       4     sample rate (little endian)
+//   8       2     channel num (little endian)
+//   10      2     bytes per sample (little endian)
+//   12      -     linear PCM data
+var pcmMagic = []byte("EPCM")
+
+const pcmHeaderSize = 12
+
+// EncodePCM reads the whole decoded stream src and returns bytes with a header
+// indicating the sample rate and the format.
+//
+// src's format must be the one noted at NewPlayer, and the sample rate must be same as that of the context.
+// Streams returned by decoders like audio/vorbis and audio/wav satisfy this.
+//
+// The result can be saved to a file and be loaded by DecodePCM later,
+// which is much faster than decoding compressed formats.
+func EncodePCM(context *Context, src io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	sampleRate := context.sampleRate
+	header := make([]byte, pcmHeaderSize)
+	copy(header[0:4], pcmMagic)
+	header[4] = byte(sampleRate)
+	header[5] = byte(sampleRate >> 8)
+	header[6] = byte(sampleRate >> 16)
+	header[7] = byte(sampleRate >> 24)
+	header[8] = byte(channelNum)
+	header[9] = byte(channelNum >> 8)
+	header[10] = byte(bytesPerSample)
+	header[11] = byte(bytesPerSample >> 8)
+	return append(header, b...), nil
+}
+
+// DecodePCM decodes bytes encoded by EncodePCM to a playable stream.
+//
+// DecodePCM returns error when src is not encoded by EncodePCM
+// or when the sample rate of src is different from that of the context.
+//
+// The returned stream shares the underlying bytes with src.
+func DecodePCM(context *Context, src []byte) (ReadSeekCloser, error) {
+	if len(src) < pcmHeaderSize || !bytes.Equal(src[0:4], pcmMagic) {
+		return nil, fmt.Errorf("audio: invalid PCM header")
+	}
+	sampleRate := int(src[4]) | int(src[5])<<8 | int(src[6])<<16 | int(src[7])<<24
+	if sampleRate != context.sampleRate {
+		return nil, fmt.Errorf("audio: sample rate must be %d but was %d", context.sampleRate, sampleRate)
+	}
+	if n := int(src[8]) | int(src[9])<<8; n != channelNum {
+		return nil, fmt.Errorf("audio: channel num must be %d but was %d", channelNum, n)
+	}
+	if n := int(src[10]) | int(src[11])<<8; n != bytesPerSample {
+		return nil, fmt.Errorf("audio: bytes per sample must be %d but was %d", bytesPerSample, n)
+	}
+	return BytesReadSeekCloser(src[pcmHeaderSize:]), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+)
+
+// The audio context always outputs interleaved samples (L, R, L, R, ...)
+// since all the drivers used by the context (ALSA, WinMM, Core Audio, OpenSL ES, Web Audio via oto)
+// accept interleaved samples.
+// Some native audio APIs (e.g. Web Audio's AudioBuffer, Core Audio's non-interleaved AudioBufferList
+// or plugin APIs like VST) require planar samples, where each channel has a separate buffer.
+// Deinterleave and Interleave convert the formats to integrate with such APIs.
+
+// Deinterleave splits interleaved stereo samples src into the left and the right channels.
+//
+// The format of src must be 16-bit little endian and 2 channels, and
+// the format of left and right is 16-bit little endian and 1 channel.
+//
+// Deinterleave panics when len(src) is not a multiple of 4.
+func Deinterleave(src []byte) (left, right []byte) {
+	const bytesPerFrame = channelNum * bytesPerSample
+	if len(src)%bytesPerFrame != 0 {
+		panic(fmt.Sprintf("audio: len(src) must be a multiple of %d but was %d", bytesPerFrame, len(src)))
+	}
+	n := len(src) / bytesPerFrame
+	left = make([]byte, n*bytesPerSample)
+	right = make([]byte, n*bytesPerSample)
+	for i := 0; i < n; i++ {
+		copy(left[i*bytesPerSample:], src[i*bytesPerFrame:i*bytesPerFrame+bytesPerSample])
+		copy(right[i*bytesPerSample:], src[i*bytesPerFrame+bytesPerSample:(i+1)*bytesPerFrame])
+	}
+	return left, right
+}
+
+// Interleave merges the left and the right channels into interleaved stereo samples.
+//
+// This is the inverse of Deinterleave.
+//
+// Interleave panics when len(left) and len(right) are different or are not a multiple of 2.
+func Interleave(left, right []byte) []byte {
+	if len(left) != len(right) {
+		panic(fmt.Sprintf("audio: len(left) (%d) and len(right) (%d) must be same", len(left), len(right)))
+	}
+	if len(left)%bytesPerSample != 0 {
+		panic(fmt.Sprintf("audio: len(left) must be a multiple of %d but was %d", bytesPerSample, len(left)))
+	}
+	const bytesPerFrame = channelNum * bytesPerSample
+	n := len(left) / bytesPerSample
+	dst := make([]byte, n*bytesPerFrame)
+	for i := 0; i < n; i++ {
+		copy(dst[i*bytesPerFrame:], left[i*bytesPerSample:(i+1)*bytesPerSample])
+		copy(dst[i*bytesPerFrame+bytesPerSample:], right[i*bytesPerSample:(i+1)*bytesPerSample])
+	}
+	return dst
+}
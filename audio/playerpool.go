@@ -0,0 +1,144 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"sync"
+)
+
+// PlayerPool is a pool of players to play the same sound many times at once.
+//
+// This is useful e.g. for sound effects like gunshots or UI clicks that are played rapidly.
+type PlayerPool struct {
+	context *Context
+	src     []byte
+	voices  []*Player
+
+	// playedAt is the counter values at which the voices were played last.
+	playedAt []int64
+	counter  int64
+
+	m sync.Mutex
+}
+
+// NewPlayerPool creates a new player pool with the given bytes and the maximum number of the voices.
+//
+// The format of src should be same as noted at NewPlayer.
+// The bytes are not copied: all the voices share src, and src must not be modified while the pool is used.
+//
+// The voices are created lazily when they are needed.
+//
+// If maxVoices is less than 1, NewPlayerPool panics.
+func NewPlayerPool(context *Context, src []byte, maxVoices int) *PlayerPool {
+	if maxVoices < 1 {
+		panic("audio: maxVoices must be equal to or more than 1")
+	}
+	return &PlayerPool{
+		context:  context,
+		src:      src,
+		voices:   make([]*Player, 0, maxVoices),
+		playedAt: make([]int64, 0, maxVoices),
+	}
+}
+
+// Play plays the sound from the start with a free voice, and returns the voice.
+//
+// If all the voices are playing, Play stops the voice played the earliest and reuses it.
+// The returned player can be used e.g. to set the volume or the pan of this playing.
+//
+// This function is concurrent-safe.
+func (p *PlayerPool) Play() (*Player, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	i := p.freeVoice()
+	if i < 0 {
+		v, err := NewPlayerFromBytes(p.context, p.src)
+		if err != nil {
+			return nil, err
+		}
+		p.voices = append(p.voices, v)
+		p.playedAt = append(p.playedAt, 0)
+		i = len(p.voices) - 1
+	}
+	v := p.voices[i]
+	if err := v.Rewind(); err != nil {
+		return nil, err
+	}
+	if err := v.Play(); err != nil {
+		return nil, err
+	}
+	p.counter++
+	p.playedAt[i] = p.counter
+	return v, nil
+}
+
+// freeVoice returns the index of the voice to play next.
+// freeVoice returns -1 when a new voice should be created.
+func (p *PlayerPool) freeVoice() int {
+	for i, v := range p.voices {
+		if !v.IsPlaying() {
+			return i
+		}
+	}
+	if len(p.voices) < cap(p.voices) {
+		return -1
+	}
+	oldest := 0
+	for i := range p.voices {
+		if p.playedAt[i] < p.playedAt[oldest] {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+// ActiveVoices returns the number of the voices currently playing.
+//
+// This function is concurrent-safe.
+func (p *PlayerPool) ActiveVoices() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	n := 0
+	for _, v := range p.voices {
+		if v.IsPlaying() {
+			n++
+		}
+	}
+	return n
+}
+
+// MaxVoices returns the maximum number of the voices.
+//
+// This function is concurrent-safe.
+func (p *PlayerPool) MaxVoices() int {
+	return cap(p.voices)
+}
+
+// Close closes all the voices.
+//
+// This function is concurrent-safe.
+func (p *PlayerPool) Close() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, v := range p.voices {
+		if err := v.Close(); err != nil {
+			return err
+		}
+	}
+	p.voices = p.voices[:0]
+	p.playedAt = p.playedAt[:0]
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+/*
+
+#include <jni.h>
+#include <stdlib.h>
+
+// Basically same as `((AudioManager)getSystemService(Context.AUDIO_SERVICE)).getProperty(AudioManager.PROPERTY_OUTPUT_SAMPLE_RATE)`.
+// This returns 0 when the property is not available (e.g. before API level 17).
+static int outputSampleRate(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx) {
+  JavaVM* vm = (JavaVM*)java_vm;
+  JNIEnv* env = (JNIEnv*)jni_env;
+  jobject context = (jobject)ctx;
+
+  const jclass android_content_Context =
+      (*env)->FindClass(env, "android/content/Context");
+  const jclass android_media_AudioManager =
+      (*env)->FindClass(env, "android/media/AudioManager");
+
+  const jobject audioService =
+      (*env)->GetStaticObjectField(
+          env, android_content_Context,
+          (*env)->GetStaticFieldID(env, android_content_Context, "AUDIO_SERVICE", "Ljava/lang/String;"));
+  const jobject audioManager =
+      (*env)->CallObjectMethod(
+          env, context,
+          (*env)->GetMethodID(env, android_content_Context, "getSystemService", "(Ljava/lang/String;)Ljava/lang/Object;"),
+          audioService);
+
+  const jfieldID propertyField =
+      (*env)->GetStaticFieldID(env, android_media_AudioManager, "PROPERTY_OUTPUT_SAMPLE_RATE", "Ljava/lang/String;");
+  if ((*env)->ExceptionCheck(env)) {
+    (*env)->ExceptionClear(env);
+    return 0;
+  }
+  const jobject property =
+      (*env)->GetStaticObjectField(env, android_media_AudioManager, propertyField);
+  const jstring value =
+      (jstring)(*env)->CallObjectMethod(
+          env, audioManager,
+          (*env)->GetMethodID(env, android_media_AudioManager, "getProperty", "(Ljava/lang/String;)Ljava/lang/String;"),
+          property);
+  if (value == NULL) {
+    return 0;
+  }
+  const char* str = (*env)->GetStringUTFChars(env, value, NULL);
+  const int rate = atoi(str);
+  (*env)->ReleaseStringUTFChars(env, value, str);
+  return rate;
+}
+
+*/
+import "C"
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/jni"
+)
+
+// nativeSampleRate returns the output sample rate of AudioManager.
+//
+// nativeSampleRate returns fallbackSampleRate when the JVM is not available yet or the property is not available.
+func nativeSampleRate() int {
+	rate := 0
+	if err := jni.RunOnJVM(func(vm, env, ctx uintptr) error {
+		rate = int(C.outputSampleRate(C.uintptr_t(vm), C.uintptr_t(env), C.uintptr_t(ctx)))
+		return nil
+	}); err != nil || rate <= 0 {
+		return fallbackSampleRate
+	}
+	return rate
+}
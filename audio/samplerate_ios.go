@@ -0,0 +1,36 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ios
+
+package audio
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework Foundation -framework AVFoundation
+//
+// #import <AVFoundation/AVFoundation.h>
+//
+// static double outputSampleRate() {
+//   return [[AVAudioSession sharedInstance] sampleRate];
+// }
+import "C"
+
+// nativeSampleRate returns the hardware sample rate of the audio session.
+func nativeSampleRate() int {
+	rate := int(C.outputSampleRate())
+	if rate <= 0 {
+		return fallbackSampleRate
+	}
+	return rate
+}
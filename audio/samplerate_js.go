@@ -0,0 +1,39 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build js
+
+package audio
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// nativeSampleRate returns the sample rate of the browser's audio output.
+func nativeSampleRate() int {
+	class := js.Global.Get("AudioContext")
+	if class == js.Undefined {
+		class = js.Global.Get("webkitAudioContext")
+	}
+	if class == js.Undefined {
+		return fallbackSampleRate
+	}
+	// Browsers limit the number of AudioContext objects. Close the one created only for querying.
+	c := class.New()
+	rate := c.Get("sampleRate").Int()
+	if c.Get("close") != js.Undefined {
+		c.Call("close")
+	}
+	return rate
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+// +build !js
+// +build !ios
+
+package audio
+
+// #cgo LDFLAGS: -framework CoreAudio
+//
+// #include <CoreAudio/CoreAudio.h>
+//
+// // outputSampleRate returns the nominal sample rate of the default output device, or 0 on failure.
+// static double outputSampleRate() {
+//   AudioObjectPropertyAddress addr = {
+//     kAudioHardwarePropertyDefaultOutputDevice,
+//     kAudioObjectPropertyScopeGlobal,
+//     kAudioObjectPropertyElementMaster,
+//   };
+//   AudioDeviceID device = kAudioObjectUnknown;
+//   UInt32 size = sizeof(device);
+//   if (AudioObjectGetPropertyData(kAudioObjectSystemObject, &addr, 0, NULL, &size, &device) != noErr) {
+//     return 0;
+//   }
+//   if (device == kAudioObjectUnknown) {
+//     return 0;
+//   }
+//   addr.mSelector = kAudioDevicePropertyNominalSampleRate;
+//   Float64 rate = 0;
+//   size = sizeof(rate);
+//   if (AudioObjectGetPropertyData(device, &addr, 0, NULL, &size, &rate) != noErr) {
+//     return 0;
+//   }
+//   return rate;
+// }
+import "C"
+
+// nativeSampleRate returns the nominal sample rate of the default output device.
+func nativeSampleRate() int {
+	rate := int(C.outputSampleRate())
+	if rate <= 0 {
+		return fallbackSampleRate
+	}
+	return rate
+}
@@ -27,8 +27,12 @@ import (
 
 // Stream is a decoded audio stream.
 type Stream struct {
-	decoded audio.ReadSeekCloser
-	size    int64
+	decoded    audio.ReadSeekCloser
+	size       int64
+	channelNum int
+
+	// src is the source stream. src is nil when the decoded data is kept on memory.
+	src audio.ReadSeekCloser
 }
 
 // Read is implementation of io.Reader's Read.
@@ -48,11 +52,41 @@ func (s *Stream) Close() error {
 	return s.decoded.Close()
 }
 
+// CanSeek returns a boolean value indicating whether the stream supports seeking.
+//
+// For a stream created by Decode, CanSeek always returns true since the decoded data is kept on memory.
+// For a stream created by DecodeStream, CanSeek returns false only when the source has
+// a method CanSeek() bool and it returns false.
+func (s *Stream) CanSeek() bool {
+	if c, ok := s.src.(interface {
+		CanSeek() bool
+	}); ok {
+		return c.CanSeek()
+	}
+	return true
+}
+
 // Size returns the size of decoded stream in bytes.
 func (s *Stream) Size() int64 {
 	return s.size
 }
 
+// SourceChannelNum returns the number of channels of the source.
+//
+// The decoded stream is always 2 channels regardless of the source.
+// Sources with 3 or more channels are downmixed to 2 channels.
+func (s *Stream) SourceChannelNum() int {
+	return s.channelNum
+}
+
+// SampleCount returns the number of samples of decoded stream per channel.
+//
+// The decoded stream is 16bit stereo, and one sample per channel is 4 bytes.
+// The duration of the stream is SampleCount() / (the sample rate of the audio context) seconds.
+func (s *Stream) SampleCount() int64 {
+	return s.size / 4
+}
+
 type decoded struct {
 	data       []float32
 	totalBytes int
@@ -60,6 +94,7 @@ type decoded struct {
 	posInBytes int
 	source     io.Closer
 	decoder    *oggvorbis.Reader
+	progress   func(decodedBytes, totalBytes int64)
 }
 
 func (d *decoded) readUntil(posInBytes int) error {
@@ -77,9 +112,12 @@ func (d *decoded) readUntil(posInBytes int) error {
 				d.data[p+i] = buffer[i]
 			}
 			d.readBytes += n * 2
+			if d.progress != nil {
+				d.progress(int64(d.readBytes), int64(d.totalBytes))
+			}
 		}
 		if err == io.EOF {
-			if err := d.source.Close(); err != nil {
+			if err := d.closeSource(); err != nil {
 				return err
 			}
 			break
@@ -140,9 +178,20 @@ func (d *decoded) Seek(offset int64, whence int) (int64, error) {
 	return next, nil
 }
 
+// closeSource closes the source if it is not closed yet.
+// The source is not needed any more after the whole data is decoded.
+func (d *decoded) closeSource() error {
+	if d.source == nil {
+		return nil
+	}
+	s := d.source
+	d.source = nil
+	return s.Close()
+}
+
 func (d *decoded) Close() error {
 	runtime.SetFinalizer(d, nil)
-	return nil
+	return d.closeSource()
 }
 
 func (d *decoded) Size() int64 {
@@ -150,17 +199,18 @@ func (d *decoded) Size() int64 {
 }
 
 // decode accepts an ogg stream and returns a decorded stream.
-func decode(in audio.ReadSeekCloser) (*decoded, int, int, error) {
+func decode(in audio.ReadSeekCloser, progress func(decodedBytes, totalBytes int64)) (*decoded, int, int, error) {
 	r, err := oggvorbis.NewReader(in)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 	d := &decoded{
-		data:       make([]float32, r.Length()*2),
-		totalBytes: int(r.Length()) * 4, // TODO: What if length is 0?
+		data:       make([]float32, r.Length()*int64(r.Channels())),
+		totalBytes: int(r.Length()) * r.Channels() * 2, // TODO: What if length is 0?
 		posInBytes: 0,
 		source:     in,
 		decoder:    r,
+		progress:   progress,
 	}
 	runtime.SetFinalizer(d, (*decoded).Close)
 	if _, err := d.Read(make([]uint8, 65536)); err != nil {
@@ -172,28 +222,190 @@ func decode(in audio.ReadSeekCloser) (*decoded, int, int, error) {
 	return d, r.Channels(), r.SampleRate(), nil
 }
 
+// streamed is a decoded stream that decodes the source on demand without keeping the decoded data.
+type streamed struct {
+	source     io.Closer
+	decoder    *oggvorbis.Reader
+	channelNum int
+	totalBytes int
+	posInBytes int
+
+	// pending is the decoded samples that are not read yet.
+	pending []float32
+	buffer  []float32
+}
+
+func newStreamed(in audio.ReadSeekCloser) (*streamed, int, error) {
+	r, err := oggvorbis.NewReader(in)
+	if err != nil {
+		return nil, 0, err
+	}
+	s := &streamed{
+		source:     in,
+		decoder:    r,
+		channelNum: r.Channels(),
+		totalBytes: int(r.Length()) * r.Channels() * 2,
+		buffer:     make([]float32, 8192),
+	}
+	return s, r.SampleRate(), nil
+}
+
+func (s *streamed) Read(b []uint8) (int, error) {
+	l := s.totalBytes - s.posInBytes
+	if l > len(b) {
+		l = len(b)
+	}
+	if l <= 0 {
+		return 0, io.EOF
+	}
+	// l must be even so that s.posInBytes is always even.
+	l = l / 2 * 2
+	for len(s.pending) < l/2 {
+		n, err := s.decoder.Read(s.buffer)
+		s.pending = append(s.pending, s.buffer[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(s.pending) < l/2 {
+		// The actual data is shorter than the reported length.
+		l = len(s.pending) * 2
+		s.totalBytes = s.posInBytes + l
+	}
+	for i := 0; i < l/2; i++ {
+		v := int16(s.pending[i] * (1<<15 - 1))
+		b[2*i] = uint8(v)
+		b[2*i+1] = uint8(v >> 8)
+	}
+	s.pending = s.pending[l/2:]
+	s.posInBytes += l
+	if s.posInBytes == s.totalBytes {
+		return l, io.EOF
+	}
+	return l, nil
+}
+
+func (s *streamed) Seek(offset int64, whence int) (int64, error) {
+	next := int64(0)
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = int64(s.posInBytes) + offset
+	case io.SeekEnd:
+		next = int64(s.totalBytes) + offset
+	}
+	// pos should be always at the boundary of samples.
+	frame := int64(2 * s.channelNum)
+	next = next / frame * frame
+	if err := s.decoder.SetPosition(next / frame); err != nil {
+		return 0, err
+	}
+	s.posInBytes = int(next)
+	s.pending = nil
+	return next, nil
+}
+
+func (s *streamed) Close() error {
+	return s.source.Close()
+}
+
+func (s *streamed) Size() int64 {
+	return int64(s.totalBytes)
+}
+
+// speakers represents the channel orders defined in the Vorbis I specification.
+// The index is the number of channels.
+var speakers = [][]convert.Speaker{
+	nil,
+	nil,
+	nil,
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontCenter, convert.SpeakerFrontRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerBackLeft, convert.SpeakerBackRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontCenter, convert.SpeakerFrontRight, convert.SpeakerBackLeft, convert.SpeakerBackRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontCenter, convert.SpeakerFrontRight, convert.SpeakerBackLeft, convert.SpeakerBackRight, convert.SpeakerLFE},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontCenter, convert.SpeakerFrontRight, convert.SpeakerSideLeft, convert.SpeakerSideRight, convert.SpeakerBackCenter, convert.SpeakerLFE},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontCenter, convert.SpeakerFrontRight, convert.SpeakerSideLeft, convert.SpeakerSideRight, convert.SpeakerBackLeft, convert.SpeakerBackRight, convert.SpeakerLFE},
+}
+
 // Decode decodes Ogg/Vorbis data to playable stream.
 //
+// The decoded stream is always 2 channels.
+// Sources with 3 to 8 channels are downmixed to 2 channels with the standard coefficients
+// based on the channel order defined in the Vorbis I specification.
+//
 // Decode returns error when the source format is wrong.
 //
-// Sample rate is automatically adjusted to fit with the audio context.
+// Sample rate is automatically adjusted to fit with the audio context
+// unless the strict mode of sample rates is enabled on the context.
 func Decode(context *audio.Context, src audio.ReadSeekCloser) (*Stream, error) {
-	decoded, channelNum, sampleRate, err := decode(src)
+	return DecodeWithProgress(context, src, nil)
+}
+
+// DecodeWithProgress is same as Decode but reports the decoding progress by calling progress.
+//
+// progress is called with the decoded bytes and the total bytes of the Ogg/Vorbis data
+// whenever a chunk is decoded. progress can be nil.
+//
+// Note that decoding is done lazily: progress is called from the goroutine calling the stream's Read or Seek.
+// To decode the whole data at loading time e.g. for a loading screen, read the stream to the end
+// (e.g. by ioutil.ReadAll) and call Seek(0, io.SeekStart) in a goroutine.
+func DecodeWithProgress(context *audio.Context, src audio.ReadSeekCloser, progress func(decodedBytes, totalBytes int64)) (*Stream, error) {
+	decoded, channelNum, sampleRate, err := decode(src, progress)
 	if err != nil {
 		return nil, err
 	}
-	if channelNum != 1 && channelNum != 2 {
-		return nil, fmt.Errorf("vorbis: number of channels must be 1 or 2 but was %d", channelNum)
+	return newStream(context, decoded, decoded.Size(), channelNum, sampleRate, nil)
+}
+
+// DecodeStream decodes Ogg/Vorbis data to playable stream lazily without keeping the decoded data on memory.
+//
+// The stream created by Decode keeps all the decoded data on memory after it is read once:
+// a 3-minute 44100Hz stereo music takes about 64MB as float32 samples.
+// On the other hand, DecodeStream decodes src on demand every time the stream is read,
+// and the memory usage is constant regardless of the length of src.
+// This is suitable for long background music.
+// The trade-off is that Seek re-decodes the data from the nearest position, and that src
+// must be kept open and must not be shared while the stream is used.
+//
+// Closing the stream closes src.
+//
+// The other behaviors are same as Decode.
+func DecodeStream(context *audio.Context, src audio.ReadSeekCloser) (*Stream, error) {
+	s, sampleRate, err := newStreamed(src)
+	if err != nil {
+		return nil, err
+	}
+	return newStream(context, s, s.Size(), s.channelNum, sampleRate, src)
+}
+
+func newStream(context *audio.Context, decoded audio.ReadSeekCloser, size int64, channelNum int, sampleRate int, src audio.ReadSeekCloser) (*Stream, error) {
+	if channelNum < 1 || len(speakers) <= channelNum {
+		return nil, fmt.Errorf("vorbis: number of channels must be 1 to %d but was %d", len(speakers)-1, channelNum)
+	}
+	if err := context.CheckSampleRate(sampleRate); err != nil {
+		return nil, err
 	}
-	var s audio.ReadSeekCloser = decoded
-	size := decoded.Size()
-	if channelNum == 1 {
+	s := decoded
+	switch {
+	case channelNum == 1:
 		s = convert.NewStereo16(s, true, false)
 		size *= 2
+	case channelNum > 2:
+		s = convert.NewDownmix(s, speakers[channelNum], false)
+		size = size / int64(channelNum) * 2
 	}
 	if sampleRate != context.SampleRate() {
 		s = convert.NewResampling(s, size, sampleRate, context.SampleRate())
 		size = size * int64(context.SampleRate()) / int64(sampleRate)
 	}
-	return &Stream{s, size}, nil
+	return &Stream{
+		decoded:    s,
+		size:       size,
+		channelNum: channelNum,
+		src:        src,
+	}, nil
 }
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package wav provides WAV (RIFF) decoder.
+// Package wav provides WAV (RIFF) decoder and encoder.
 package wav
 
 import (
@@ -26,8 +26,10 @@ import (
 
 // Stream is a decoded audio stream.
 type Stream struct {
-	inner audio.ReadSeekCloser
-	size  int64
+	inner      audio.ReadSeekCloser
+	size       int64
+	src        audio.ReadSeekCloser
+	channelNum int
 }
 
 // Read is implementation of io.Reader's Read.
@@ -47,11 +49,40 @@ func (s *Stream) Close() error {
 	return s.inner.Close()
 }
 
+// CanSeek returns a boolean value indicating whether the stream supports seeking.
+//
+// As a WAV stream seeks the source, CanSeek returns false only when the source has
+// a method CanSeek() bool and it returns false.
+func (s *Stream) CanSeek() bool {
+	if c, ok := s.src.(interface {
+		CanSeek() bool
+	}); ok {
+		return c.CanSeek()
+	}
+	return true
+}
+
 // Size returns the size of decoded stream in bytes.
 func (s *Stream) Size() int64 {
 	return s.size
 }
 
+// SourceChannelNum returns the number of channels of the source.
+//
+// The decoded stream is always 2 channels regardless of the source.
+// Sources with 3 or more channels are downmixed to 2 channels.
+func (s *Stream) SourceChannelNum() int {
+	return s.channelNum
+}
+
+// SampleCount returns the number of samples of decoded stream per channel.
+//
+// The decoded stream is 16bit stereo, and one sample per channel is 4 bytes.
+// The duration of the stream is SampleCount() / (the sample rate of the audio context) seconds.
+func (s *Stream) SampleCount() int64 {
+	return s.size / 4
+}
+
 type stream struct {
 	src        audio.ReadSeekCloser
 	headerSize int64
@@ -108,14 +139,35 @@ func (s *stream) Size() int64 {
 	return s.dataSize
 }
 
+// speakers represents the default channel orders of WAV (RIFF) data.
+// The index is the number of channels.
+var speakers = [][]convert.Speaker{
+	nil,
+	nil,
+	nil,
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerFrontCenter},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerBackLeft, convert.SpeakerBackRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerFrontCenter, convert.SpeakerBackLeft, convert.SpeakerBackRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerFrontCenter, convert.SpeakerLFE, convert.SpeakerBackLeft, convert.SpeakerBackRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerFrontCenter, convert.SpeakerLFE, convert.SpeakerBackCenter, convert.SpeakerSideLeft, convert.SpeakerSideRight},
+	{convert.SpeakerFrontLeft, convert.SpeakerFrontRight, convert.SpeakerFrontCenter, convert.SpeakerLFE, convert.SpeakerBackLeft, convert.SpeakerBackRight, convert.SpeakerSideLeft, convert.SpeakerSideRight},
+}
+
 // Decode decodes WAV (RIFF) data to playable stream.
 //
-// The format must be 1 or 2 channels, 8bit or 16bit little endian PCM.
+// The format must be 1 to 8 channels, 8bit or 16bit little endian PCM.
 // The format is converted into 2 channels and 16bit.
+// Sources with 3 or more channels are downmixed with the standard coefficients
+// based on the default channel order of WAV. The channel mask of WAVE_FORMAT_EXTENSIBLE is ignored.
+//
+// The stream reads src on demand and doesn't keep the decoded data on memory,
+// so the memory usage is constant regardless of the length of src.
+// Closing the stream closes src.
 //
 // Decode returns error when the source format is wrong.
 //
-// Sample rate is automatically adjusted to fit with the audio context.
+// Sample rate is automatically adjusted to fit with the audio context
+// unless the strict mode of sample rates is enabled on the context.
 func Decode(context *audio.Context, src audio.ReadSeekCloser) (*Stream, error) {
 	buf := make([]byte, 12)
 	n, err := io.ReadFull(src, buf)
@@ -137,7 +189,7 @@ func Decode(context *audio.Context, src audio.ReadSeekCloser) (*Stream, error) {
 	headerSize := int64(len(buf))
 	sampleRateFrom := 0
 	sampleRateTo := 0
-	mono := false
+	channelNum := 0
 	bitsPerSample := 0
 chunks:
 	for {
@@ -166,23 +218,25 @@ chunks:
 				return nil, err
 			}
 			format := int(buf[0]) | int(buf[1])<<8
+			// WAVE_FORMAT_EXTENSIBLE, which is usually used for multi-channel data, has the actual format in the sub format.
+			if format == 0xfffe && size >= 26 {
+				format = int(buf[24]) | int(buf[25])<<8
+			}
 			if format != 1 {
 				return nil, fmt.Errorf("wav: format must be linear PCM")
 			}
-			channelNum := int(buf[2]) | int(buf[3])<<8
-			switch channelNum {
-			case 1:
-				mono = true
-			case 2:
-				mono = false
-			default:
-				return nil, fmt.Errorf("wav: channel num must be 1 or 2 but was %d", channelNum)
+			channelNum = int(buf[2]) | int(buf[3])<<8
+			if channelNum < 1 || len(speakers) <= channelNum {
+				return nil, fmt.Errorf("wav: channel num must be 1 to %d but was %d", len(speakers)-1, channelNum)
 			}
 			bitsPerSample = int(buf[14]) | int(buf[15])<<8
 			if bitsPerSample != 8 && bitsPerSample != 16 {
 				return nil, fmt.Errorf("wav: bits per sample must be 8 or 16 but was %d", bitsPerSample)
 			}
 			sampleRate := int64(buf[4]) | int64(buf[5])<<8 | int64(buf[6])<<16 | int64(buf[7])<<24
+			if err := context.CheckSampleRate(int(sampleRate)); err != nil {
+				return nil, err
+			}
 			if int64(context.SampleRate()) != sampleRate {
 				sampleRateFrom = int(sampleRate)
 				sampleRateTo = context.SampleRate()
@@ -209,7 +263,12 @@ chunks:
 		dataSize:   dataSize,
 		remaining:  dataSize,
 	}
-	if mono || bitsPerSample != 16 {
+	mono := channelNum == 1
+	switch {
+	case channelNum > 2:
+		s = convert.NewDownmix(s, speakers[channelNum], bitsPerSample != 16)
+		dataSize = dataSize / int64(channelNum*bitsPerSample/8) * 4
+	case mono || bitsPerSample != 16:
 		s = convert.NewStereo16(s, mono, bitsPerSample != 16)
 		if mono {
 			dataSize *= 2
@@ -222,5 +281,5 @@ chunks:
 		s = convert.NewResampling(s, dataSize, sampleRateFrom, sampleRateTo)
 		dataSize = dataSize * int64(sampleRateTo) / int64(sampleRateFrom)
 	}
-	return &Stream{s, dataSize}, nil
+	return &Stream{s, dataSize, src, channelNum}, nil
 }
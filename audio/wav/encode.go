@@ -0,0 +1,81 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wav
+
+import (
+	"fmt"
+	"io"
+)
+
+// Encode writes WAV (RIFF) data of the given PCM to w.
+//
+// pcm must be 16bit little endian linear PCM with the given number of channels.
+// For example, bytes read from a stream returned by Decode or audio/vorbis's Decode are
+// 2 channel 16bit PCM with the sample rate of the audio context.
+//
+// channels must be 1 or 2.
+//
+// Encode returns error when the arguments are invalid or writing to w fails.
+func Encode(w io.Writer, sampleRate, channels int, pcm []byte) error {
+	if sampleRate <= 0 {
+		return fmt.Errorf("wav: sample rate must be positive but was %d", sampleRate)
+	}
+	if channels != 1 && channels != 2 {
+		return fmt.Errorf("wav: channel num must be 1 or 2 but was %d", channels)
+	}
+	const bytesPerSample = 2
+	blockAlign := channels * bytesPerSample
+	if len(pcm)%blockAlign != 0 {
+		return fmt.Errorf("wav: len(pcm) must be a multiple of %d but was %d", blockAlign, len(pcm))
+	}
+	byteRate := sampleRate * blockAlign
+
+	const headerSize = 44
+	header := make([]byte, headerSize)
+	copy(header[0:4], "RIFF")
+	putUint32(header[4:8], uint32(headerSize-8+len(pcm)))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	putUint32(header[16:20], 16)
+	// Linear PCM
+	putUint16(header[20:22], 1)
+	putUint16(header[22:24], uint16(channels))
+	putUint32(header[24:28], uint32(sampleRate))
+	putUint32(header[28:32], uint32(byteRate))
+	putUint16(header[32:34], uint16(blockAlign))
+	putUint16(header[34:36], bytesPerSample*8)
+	copy(header[36:40], "data")
+	putUint32(header[40:44], uint32(len(pcm)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(pcm); err != nil {
+		return err
+	}
+	return nil
+}
+
+func putUint16(b []byte, v uint16) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+}
+
+func putUint32(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+	b[3] = byte(v >> 24)
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/audio"
+	. "github.com/hajimehoshi/ebiten/audio/wav"
+)
+
+type bytesReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (b *bytesReadSeekCloser) Close() error {
+	return nil
+}
+
+const sampleRate = 44100
+
+var context *audio.Context
+
+func TestMain(m *testing.M) {
+	c, err := audio.NewContext(sampleRate)
+	if err != nil {
+		panic(err)
+	}
+	context = c
+	os.Exit(m.Run())
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	// jab.wav is 44100 Hz 2 channel 16bit PCM.
+	f, err := os.Open("../../examples/_resources/audio/jab.wav")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	defer f.Close()
+	s0, err := Decode(context, f)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	pcm0, err := ioutil.ReadAll(s0)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, sampleRate, 2, pcm0); err != nil {
+		t.Fatal(err)
+		return
+	}
+	s1, err := Decode(context, &bytesReadSeekCloser{bytes.NewReader(buf.Bytes())})
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := s1.Size(), s0.Size(); got != want {
+		t.Errorf("s1.Size(): got %d, want: %d", got, want)
+	}
+	pcm1, err := ioutil.ReadAll(s1)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if !bytes.Equal(pcm0, pcm1) {
+		t.Errorf("the decoded PCM doesn't match with the original one")
+	}
+}
+
+func TestEncodeInvalidLength(t *testing.T) {
+	if err := Encode(ioutil.Discard, 44100, 2, make([]byte, 3)); err == nil {
+		t.Errorf("Encode must return an error for an invalid length")
+	}
+}
+
+func TestDecodeDownmix(t *testing.T) {
+	// 3 channels: front left, front right and front center.
+	const channelNum = 3
+	samples := []int16{
+		1000, 0, 0,
+		0, 1000, 0,
+		0, 0, 1000,
+	}
+	buf := &bytes.Buffer{}
+	dataSize := len(samples) * 2
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, uint16(1))
+	binary.Write(buf, binary.LittleEndian, uint16(channelNum))
+	binary.Write(buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(buf, binary.LittleEndian, uint32(sampleRate*channelNum*2))
+	binary.Write(buf, binary.LittleEndian, uint16(channelNum*2))
+	binary.Write(buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	binary.Write(buf, binary.LittleEndian, samples)
+
+	s, err := Decode(context, &bytesReadSeekCloser{bytes.NewReader(buf.Bytes())})
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := s.SourceChannelNum(), channelNum; got != want {
+		t.Errorf("s.SourceChannelNum(): got %d, want: %d", got, want)
+	}
+	if got, want := s.SampleCount(), int64(3); got != want {
+		t.Errorf("s.SampleCount(): got %d, want: %d", got, want)
+	}
+	pcm, err := ioutil.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	out := make([]int16, len(pcm)/2)
+	if err := binary.Read(bytes.NewReader(pcm), binary.LittleEndian, out); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if len(out) != 6 {
+		t.Fatalf("len(out): got %d, want: 6", len(out))
+	}
+	// The left channel is only in the left output, the right channel is only in the right output,
+	// and the center channel is in both outputs equally.
+	if out[0] <= 0 || out[1] != 0 {
+		t.Errorf("front left: got (%d, %d)", out[0], out[1])
+	}
+	if out[2] != 0 || out[3] <= 0 {
+		t.Errorf("front right: got (%d, %d)", out[2], out[3])
+	}
+	if out[4] <= 0 || out[4] != out[5] {
+		t.Errorf("front center: got (%d, %d)", out[4], out[5])
+	}
+}
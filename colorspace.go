@@ -0,0 +1,48 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+// A ColorSpace represents a color capability of a display.
+type ColorSpace int
+
+// ColorSpaces
+const (
+	// ColorSpaceSRGB represents a standard dynamic range display with the sRGB gamut.
+	ColorSpaceSRGB = ColorSpace(ui.ColorSpaceSRGB)
+
+	// ColorSpaceWideGamut represents a standard dynamic range display with a gamut wider than sRGB
+	// (e.g. Display P3).
+	ColorSpaceWideGamut = ColorSpace(ui.ColorSpaceWideGamut)
+
+	// ColorSpaceHDR represents a high dynamic range display.
+	ColorSpaceHDR = ColorSpace(ui.ColorSpaceHDR)
+)
+
+// DisplayColorSpace returns the color capability of the display where the game is shown.
+//
+// This is a diagnostic function e.g. to decide whether tone mapping is needed.
+// Ebiten itself always renders in sRGB: the colors are not converted for wide-gamut or HDR displays.
+//
+// On browsers, DisplayColorSpace uses the media queries 'dynamic-range' and 'color-gamut'.
+// On desktops and mobiles, the color capability is not available, and DisplayColorSpace returns ColorSpaceSRGB.
+//
+// This function is concurrent-safe.
+func DisplayColorSpace() ColorSpace {
+	return ColorSpace(ui.DisplayColorSpace())
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+// A CursorMode represents a mode of the mouse cursor.
+type CursorMode int
+
+// CursorModes
+const (
+	// CursorModeVisible shows the cursor and the cursor moves freely.
+	CursorModeVisible = CursorMode(ui.CursorModeVisible)
+
+	// CursorModeHidden hides the cursor while the cursor is on the window.
+	CursorModeHidden = CursorMode(ui.CursorModeHidden)
+
+	// CursorModeCaptured hides the cursor and locks it to the window.
+	// The cursor movement is unbounded and can be got by CursorDelta.
+	CursorModeCaptured = CursorMode(ui.CursorModeCaptured)
+)
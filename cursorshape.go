@@ -0,0 +1,43 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+// A CursorShape represents a shape of the mouse cursor.
+type CursorShape int
+
+// CursorShapes
+const (
+	// CursorShapeDefault represents the default arrow cursor.
+	CursorShapeDefault = CursorShape(ui.CursorShapeDefault)
+
+	// CursorShapeText represents the I-beam cursor for text input.
+	CursorShapeText = CursorShape(ui.CursorShapeText)
+
+	// CursorShapeCrosshair represents the crosshair cursor.
+	CursorShapeCrosshair = CursorShape(ui.CursorShapeCrosshair)
+
+	// CursorShapePointer represents the hand cursor e.g. for links and buttons.
+	CursorShapePointer = CursorShape(ui.CursorShapePointer)
+
+	// CursorShapeEWResize represents the horizontal resize cursor.
+	CursorShapeEWResize = CursorShape(ui.CursorShapeEWResize)
+
+	// CursorShapeNSResize represents the vertical resize cursor.
+	CursorShapeNSResize = CursorShape(ui.CursorShapeNSResize)
+)
@@ -0,0 +1,51 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+)
+
+// ApplyColorKey returns a copy of img where the pixels of the color key are transparent.
+//
+// A pixel matches the key when each of its R, G and B components differs from the key's
+// by at most tolerance. The alpha values are not compared.
+// Matching pixels become fully transparent, and the other pixels are kept as they are.
+//
+// This is useful for old assets that use a color key (e.g. magenta) instead of an alpha channel.
+func ApplyColorKey(img image.Image, key color.Color, tolerance uint8) *image.NRGBA {
+	k := color.NRGBAModel.Convert(key).(color.NRGBA)
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		for i := b.Min.X; i < b.Max.X; i++ {
+			c := color.NRGBAModel.Convert(img.At(i, j)).(color.NRGBA)
+			if channelClose(c.R, k.R, tolerance) && channelClose(c.G, k.G, tolerance) && channelClose(c.B, k.B, tolerance) {
+				continue
+			}
+			dst.SetNRGBA(i, j, c)
+		}
+	}
+	return dst
+}
+
+// channelClose returns true if the color channel values x and y differ by at most tolerance.
+func channelClose(x, y, tolerance uint8) bool {
+	if x < y {
+		return y-x <= tolerance
+	}
+	return x-y <= tolerance
+}
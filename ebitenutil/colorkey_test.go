@@ -0,0 +1,78 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestChannelClose(t *testing.T) {
+	cases := []struct {
+		X         uint8
+		Y         uint8
+		Tolerance uint8
+		Want      bool
+	}{
+		{0, 0, 0, true},
+		{10, 10, 0, true},
+		{10, 11, 0, false},
+		{11, 10, 0, false},
+		{10, 13, 3, true},
+		{13, 10, 3, true},
+		{10, 14, 3, false},
+		{0, 255, 254, false},
+		{0, 255, 255, true},
+		{255, 0, 255, true},
+	}
+	for _, c := range cases {
+		if got := channelClose(c.X, c.Y, c.Tolerance); got != c.Want {
+			t.Errorf("channelClose(%d, %d, %d): got %v, want: %v", c.X, c.Y, c.Tolerance, got, c.Want)
+		}
+	}
+}
+
+func TestApplyColorKey(t *testing.T) {
+	magenta := color.NRGBA{0xff, 0, 0xff, 0xff}
+	cases := []struct {
+		In        color.NRGBA
+		Tolerance uint8
+		Out       color.NRGBA
+	}{
+		{magenta, 0, color.NRGBA{}},
+		{color.NRGBA{0xfe, 0, 0xff, 0xff}, 0, color.NRGBA{0xfe, 0, 0xff, 0xff}},
+		{color.NRGBA{0xfe, 0x01, 0xfd, 0xff}, 2, color.NRGBA{}},
+		{color.NRGBA{0xfe, 0x03, 0xfd, 0xff}, 2, color.NRGBA{0xfe, 0x03, 0xfd, 0xff}},
+		// The alpha values are not compared.
+		{color.NRGBA{0xff, 0, 0xff, 0x80}, 0, color.NRGBA{}},
+		{color.NRGBA{0, 0xff, 0, 0xff}, 16, color.NRGBA{0, 0xff, 0, 0xff}},
+	}
+	for _, c := range cases {
+		img := image.NewNRGBA(image.Rect(1, 1, 3, 2))
+		img.SetNRGBA(1, 1, c.In)
+		img.SetNRGBA(2, 1, color.NRGBA{0, 0, 0, 0xff})
+		got := ApplyColorKey(img, magenta, c.Tolerance)
+		if got.Bounds() != img.Bounds() {
+			t.Errorf("ApplyColorKey(%v, %d).Bounds(): got %v, want: %v", c.In, c.Tolerance, got.Bounds(), img.Bounds())
+		}
+		if got := got.NRGBAAt(1, 1); got != c.Out {
+			t.Errorf("ApplyColorKey(%v, %d) at (1, 1): got %v, want: %v", c.In, c.Tolerance, got, c.Out)
+		}
+		if got, want := got.NRGBAAt(2, 1), (color.NRGBA{0, 0, 0, 0xff}); got != want {
+			t.Errorf("ApplyColorKey(%v, %d) at (2, 1): got %v, want: %v", c.In, c.Tolerance, got, want)
+		}
+	}
+}
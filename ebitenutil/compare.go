@@ -0,0 +1,76 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin linux windows
+// +build !android
+// +build !ios
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// CompareImages compares the pixels of got with the golden image file at goldenPath (e.g. a PNG file).
+//
+// The colors are compared as alpha-premultiplied values, and each channel can differ up to tolerance.
+// CompareImages returns nil when the images match. Otherwise, CompareImages returns an error
+// that reports the sizes or the first mismatching pixel in the row-major order.
+//
+// This is useful for visual regression tests. CompareImages reads back the pixels from VRAM, and
+// can't be called before the main loop (ebiten.Run) starts.
+func CompareImages(got *ebiten.Image, goldenPath string, tolerance uint8) error {
+	file, err := OpenFile(goldenPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	golden, _, err := image.Decode(file)
+	if err != nil {
+		return err
+	}
+	img, err := got.ToImage()
+	if err != nil {
+		return err
+	}
+	gs, ws := img.Bounds().Size(), golden.Bounds().Size()
+	if gs != ws {
+		return fmt.Errorf("ebitenutil: image size: got %v, want: %v", gs, ws)
+	}
+	b := golden.Bounds()
+	for j := 0; j < gs.Y; j++ {
+		for i := 0; i < gs.X; i++ {
+			c0 := img.RGBAAt(i, j)
+			c1 := color.RGBAModel.Convert(golden.At(b.Min.X+i, b.Min.Y+j)).(color.RGBA)
+			if !colorsClose(c0, c1, tolerance) {
+				return fmt.Errorf("ebitenutil: pixel at (%d, %d): got %v, want: %v (tolerance: %d)", i, j, c0, c1, tolerance)
+			}
+		}
+	}
+	return nil
+}
+
+func colorsClose(c0, c1 color.RGBA, tolerance uint8) bool {
+	return channelClose(c0.R, c1.R, tolerance) &&
+		channelClose(c0.G, c1.G, tolerance) &&
+		channelClose(c0.B, c1.B, tolerance) &&
+		channelClose(c0.A, c1.A, tolerance)
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin linux windows
+// +build !android
+// +build !ios
+
+package ebitenutil
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorsClose(t *testing.T) {
+	cases := []struct {
+		C0        color.RGBA
+		C1        color.RGBA
+		Tolerance uint8
+		Want      bool
+	}{
+		{color.RGBA{1, 2, 3, 4}, color.RGBA{1, 2, 3, 4}, 0, true},
+		{color.RGBA{1, 2, 3, 4}, color.RGBA{1, 2, 3, 5}, 0, false},
+		{color.RGBA{1, 2, 3, 4}, color.RGBA{0, 3, 2, 5}, 1, true},
+		{color.RGBA{1, 2, 3, 4}, color.RGBA{3, 2, 3, 4}, 1, false},
+		{color.RGBA{0x80, 0x80, 0x80, 0x80}, color.RGBA{0x7f, 0x81, 0x80, 0x80}, 1, true},
+		// Each channel is compared independently. Only the alpha differs here.
+		{color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0xff}, 0xfe, false},
+	}
+	for _, c := range cases {
+		if got := colorsClose(c.C0, c.C1, c.Tolerance); got != c.Want {
+			t.Errorf("colorsClose(%v, %v, %d): got %v, want: %v", c.C0, c.C1, c.Tolerance, got, c.Want)
+		}
+	}
+}
@@ -67,6 +67,15 @@ func DebugPrint(image *ebiten.Image, str string) error {
 	return nil
 }
 
+// PrewarmDebugPrint loads the glyph atlas for DebugPrint and DebugPrintOutlined.
+//
+// The atlas is decoded and uploaded at the first call of DebugPrint by default,
+// and this might cause a hitch at the frame. Call PrewarmDebugPrint e.g. at loading time to avoid this.
+// The atlas includes all the ASCII characters, so there is no need to specify characters.
+func PrewarmDebugPrint() {
+	defaultDebugPrintState.initIfNeeded()
+}
+
 func (d *debugPrintState) drawText(rt *ebiten.Image, str string, x, y int, c color.Color) {
 	ur, ug, ub, ua := c.RGBA()
 	const max = math.MaxUint16
@@ -87,8 +96,32 @@ func (d *debugPrintState) drawText(rt *ebiten.Image, str string, x, y int, c col
 	_ = rt.DrawImage(d.textImage, op)
 }
 
-// DebugPrint prints the given text str on the given image r.
-func (d *debugPrintState) DebugPrint(r *ebiten.Image, str string) {
+// DebugPrintOutlined draws the string str at (x, y) on the image with the fill color,
+// surrounded by the outline color.
+//
+// This is useful to make text readable on busy backgrounds.
+//
+// DebugPrintOutlined always returns nil.
+func DebugPrintOutlined(image *ebiten.Image, str string, x, y int, fill, outline color.Color) error {
+	defaultDebugPrintState.DebugPrintOutlined(image, str, x, y, fill, outline)
+	return nil
+}
+
+// DebugPrintOutlined prints the given text str at (x, y) on the given image r with an outline.
+func (d *debugPrintState) DebugPrintOutlined(r *ebiten.Image, str string, x, y int, fill, outline color.Color) {
+	d.initIfNeeded()
+	for j := -1; j <= 1; j++ {
+		for i := -1; i <= 1; i++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			d.drawText(r, str, x+i, y+j, outline)
+		}
+	}
+	d.drawText(r, str, x, y, fill)
+}
+
+func (d *debugPrintState) initIfNeeded() {
 	if d.textImage == nil {
 		img := assets.TextImage()
 		d.textImage, _ = ebiten.NewImageFromImage(img, ebiten.FilterNearest)
@@ -97,6 +130,11 @@ func (d *debugPrintState) DebugPrint(r *ebiten.Image, str string) {
 		width, height := 256, 256
 		d.debugPrintRenderTarget, _ = ebiten.NewImage(width, height, ebiten.FilterNearest)
 	}
+}
+
+// DebugPrint prints the given text str on the given image r.
+func (d *debugPrintState) DebugPrint(r *ebiten.Image, str string) {
+	d.initIfNeeded()
 	d.drawText(r, str, 1, 1, color.NRGBA{0x00, 0x00, 0x00, 0x80})
 	d.drawText(r, str, 0, 0, color.NRGBA{0xff, 0xff, 0xff, 0xff})
 }
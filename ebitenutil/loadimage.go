@@ -20,6 +20,11 @@ package ebitenutil
 
 import (
 	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"sync"
+	"time"
 
 	"github.com/hajimehoshi/ebiten"
 )
@@ -47,3 +52,133 @@ func NewImageFromFile(path string, filter ebiten.Filter) (*ebiten.Image, image.I
 	}
 	return img2, img, err
 }
+
+// NewImageFromFileColorKey loads the file path and returns ebiten.Image and image.Image
+// where the pixels of the color key are transparent.
+//
+// Only the pixels exactly matching key become transparent.
+// Use ApplyColorKey and ebiten.NewImageFromImage to allow near matches.
+//
+// The returned image.Image is the decoded image before applying the color key.
+//
+// The same notes as NewImageFromFile are applied.
+func NewImageFromFileColorKey(path string, key color.Color, filter ebiten.Filter) (*ebiten.Image, image.Image, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	img2, err := ebiten.NewImageFromImage(ApplyColorKey(img, key, 0), filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img2, img, err
+}
+
+// ImageLoadResult represents a result of loading an image file by NewImagesFromFiles.
+type ImageLoadResult struct {
+	// Image is the loaded image. Image is nil when Err is not nil.
+	Image *ebiten.Image
+
+	// Path is the file path of the image.
+	Path string
+
+	// Err is the error that occurred while opening, decoding or creating the image.
+	Err error
+}
+
+// NewImagesFromFiles loads the files paths concurrently and returns a channel to receive the results.
+//
+// The channel receives exactly one result for each path, and is closed after all the results are sent.
+// The order of the results is not guaranteed to be same as paths.
+// Even when some files fail to load, the other files are loaded, and each error is reported with its path.
+//
+// The same notes as NewImageFromFile are applied.
+func NewImagesFromFiles(paths []string, filter ebiten.Filter) <-chan ImageLoadResult {
+	ch := make(chan ImageLoadResult, len(paths))
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			img, _, err := NewImageFromFile(path, filter)
+			ch <- ImageLoadResult{
+				Image: img,
+				Path:  path,
+				Err:   err,
+			}
+		}(path)
+	}
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// NewImagesFromGIF loads the animated GIF file path and returns the frames and their delays.
+//
+// Each frame is composed with the previous frames according to the frame's disposal method,
+// so that each returned image has the whole logical screen of the GIF.
+//
+// The same notes as NewImageFromFile are applied.
+func NewImagesFromGIF(path string, filter ebiten.Filter) ([]*ebiten.Image, []time.Duration, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+	imgs := make([]*ebiten.Image, 0, len(g.Image))
+	delays := make([]time.Duration, 0, len(g.Image))
+	for i, frame := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		var prev *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			prev = image.NewRGBA(bounds)
+			copy(prev.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		img, err := ebiten.NewImageFromImage(canvas, filter)
+		if err != nil {
+			for _, img := range imgs {
+				_ = img.Dispose()
+			}
+			return nil, nil, err
+		}
+		imgs = append(imgs, img)
+		delay := 0
+		if i < len(g.Delay) {
+			delay = g.Delay[i]
+		}
+		// The unit of GIF's delay is 1/100 second.
+		delays = append(delays, time.Duration(delay)*10*time.Millisecond)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.ZP, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = prev
+		}
+	}
+	return imgs, delays, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+type scrollingImagePart struct {
+	dst image.Rectangle
+	src image.Rectangle
+}
+
+type scrollingImageParts []scrollingImagePart
+
+func (s scrollingImageParts) Len() int {
+	return len(s)
+}
+
+func (s scrollingImageParts) Dst(i int) (x0, y0, x1, y1 int) {
+	r := s[i].dst
+	return r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+func (s scrollingImageParts) Src(i int) (x0, y0, x1, y1 int) {
+	r := s[i].src
+	return r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+// mod returns x modulo y in [0, y).
+func mod(x, y int) int {
+	return ((x % y) + y) % y
+}
+
+// scrollingSegments splits [min, max) into segments so that each segment maps to a continuous range
+// of a tile with the given size shifted by offset.
+func scrollingSegments(min, max, size, offset int) (dsts, srcs [][2]int) {
+	for d := min; d < max; {
+		s := mod(d-min-offset, size)
+		l := size - s
+		if d+l > max {
+			l = max - d
+		}
+		dsts = append(dsts, [2]int{d, d + l})
+		srcs = append(srcs, [2]int{s, s + l})
+		d += l
+	}
+	return
+}
+
+// DrawScrolling draws the image src tiled repeatedly in the rectangle dstRect on dst,
+// shifted by (offsetX, offsetY).
+//
+// The pixel of src at (0, 0) appears at (dstRect.Min.X + offsetX, dstRect.Min.Y + offsetY), and src
+// is repeated infinitely in both directions. Only the region in dstRect is drawn.
+// This is useful for looping backgrounds like parallax skies or grounds:
+// increase or decrease the offset every frame to scroll.
+//
+// The offsets are rounded to the nearest integers so that the tiles are snapped to the pixel grid.
+//
+// All the tiles are drawn by one DrawImage call.
+func DrawScrolling(dst, src *ebiten.Image, offsetX, offsetY float64, dstRect image.Rectangle) error {
+	if dstRect.Empty() {
+		return nil
+	}
+	sw, sh := src.Size()
+	ox := int(math.Floor(offsetX + 0.5))
+	oy := int(math.Floor(offsetY + 0.5))
+	dxs, sxs := scrollingSegments(dstRect.Min.X, dstRect.Max.X, sw, ox)
+	dys, sys := scrollingSegments(dstRect.Min.Y, dstRect.Max.Y, sh, oy)
+	parts := make(scrollingImageParts, 0, len(dxs)*len(dys))
+	for j := range dys {
+		for i := range dxs {
+			parts = append(parts, scrollingImagePart{
+				dst: image.Rect(dxs[i][0], dys[j][0], dxs[i][1], dys[j][1]),
+				src: image.Rect(sxs[i][0], sys[j][0], sxs[i][1], sys[j][1]),
+			})
+		}
+	}
+	op := &ebiten.DrawImageOptions{
+		ImageParts: parts,
+	}
+	return dst.DrawImage(src, op)
+}
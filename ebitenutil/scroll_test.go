@@ -0,0 +1,73 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScrollingSegments(t *testing.T) {
+	cases := []struct {
+		Min    int
+		Max    int
+		Size   int
+		Offset int
+		Dsts   [][2]int
+		Srcs   [][2]int
+	}{
+		// No offset: the tile is repeated from the start.
+		{
+			Min: 0, Max: 10, Size: 4, Offset: 0,
+			Dsts: [][2]int{{0, 4}, {4, 8}, {8, 10}},
+			Srcs: [][2]int{{0, 4}, {0, 4}, {0, 2}},
+		},
+		// A positive offset wraps the end of the tile around to the start.
+		{
+			Min: 0, Max: 8, Size: 4, Offset: 1,
+			Dsts: [][2]int{{0, 1}, {1, 5}, {5, 8}},
+			Srcs: [][2]int{{3, 4}, {0, 4}, {0, 3}},
+		},
+		// A negative offset starts in the middle of the tile.
+		{
+			Min: 0, Max: 6, Size: 4, Offset: -3,
+			Dsts: [][2]int{{0, 1}, {1, 5}, {5, 6}},
+			Srcs: [][2]int{{3, 4}, {0, 4}, {0, 1}},
+		},
+		// An offset larger than the tile size is equivalent to the offset modulo the size.
+		{
+			Min: 2, Max: 7, Size: 4, Offset: 9,
+			Dsts: [][2]int{{2, 3}, {3, 7}},
+			Srcs: [][2]int{{3, 4}, {0, 4}},
+		},
+		// The range is shorter than the rest of the tile.
+		{
+			Min: 10, Max: 12, Size: 8, Offset: 3,
+			Dsts: [][2]int{{10, 12}},
+			Srcs: [][2]int{{5, 7}},
+		},
+		{
+			Min: 0, Max: 0, Size: 4, Offset: 1,
+			Dsts: nil,
+			Srcs: nil,
+		},
+	}
+	for _, c := range cases {
+		dsts, srcs := scrollingSegments(c.Min, c.Max, c.Size, c.Offset)
+		if !reflect.DeepEqual(dsts, c.Dsts) || !reflect.DeepEqual(srcs, c.Srcs) {
+			t.Errorf("scrollingSegments(%d, %d, %d, %d): got (%v, %v), want: (%v, %v)", c.Min, c.Max, c.Size, c.Offset, dsts, srcs, c.Dsts, c.Srcs)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+var (
+	whiteImage     *ebiten.Image
+	whiteImageLock sync.Mutex
+)
+
+func ensureWhiteImage() (*ebiten.Image, error) {
+	whiteImageLock.Lock()
+	defer whiteImageLock.Unlock()
+	if whiteImage != nil {
+		return whiteImage, nil
+	}
+	img, err := ebiten.NewImage(1, 1, ebiten.FilterNearest)
+	if err != nil {
+		return nil, err
+	}
+	if err := img.Fill(color.White); err != nil {
+		return nil, err
+	}
+	whiteImage = img
+	return whiteImage, nil
+}
+
+type rectsImageParts []image.Rectangle
+
+func (r rectsImageParts) Len() int {
+	return len(r)
+}
+
+func (r rectsImageParts) Dst(i int) (x0, y0, x1, y1 int) {
+	return r[i].Min.X, r[i].Min.Y, r[i].Max.X, r[i].Max.Y
+}
+
+func (r rectsImageParts) Src(i int) (x0, y0, x1, y1 int) {
+	return 0, 0, 1, 1
+}
+
+// DrawRects fills the rectangles rects on dst with the colors clrs.
+//
+// clrs[i] is used for rects[i]. If len(clrs) is 1, the color is used for all the rectangles.
+// Otherwise, if len(clrs) is different from len(rects), DrawRects panics.
+//
+// DrawRects is much faster than drawing rectangles one by one with DrawImage,
+// since rectangles with the same color are drawn by one draw call.
+// Note that this means the drawing order of overlapping rectangles with different colors is
+// not preserved: the rectangles are drawn in order of the first appearance of each color.
+//
+// This is useful e.g. for tilemaps or debug visualizations.
+func DrawRects(dst *ebiten.Image, rects []image.Rectangle, clrs []color.Color) error {
+	if len(clrs) != 1 && len(clrs) != len(rects) {
+		panic(fmt.Sprintf("ebitenutil: len(clrs) must be 1 or len(rects) (%d) but was %d", len(rects), len(clrs)))
+	}
+	if len(rects) == 0 {
+		return nil
+	}
+	img, err := ensureWhiteImage()
+	if err != nil {
+		return err
+	}
+	if len(clrs) == 1 {
+		return drawRects(dst, img, rects, clrs[0])
+	}
+	order := []color.RGBA64{}
+	groups := map[color.RGBA64][]image.Rectangle{}
+	for i, r := range rects {
+		c := color.RGBA64Model.Convert(clrs[i]).(color.RGBA64)
+		if _, ok := groups[c]; !ok {
+			order = append(order, c)
+		}
+		groups[c] = append(groups[c], r)
+	}
+	for _, c := range order {
+		if err := drawRects(dst, img, groups[c], c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// colorScale returns the non-premultiplied color values of c to be used with ColorM.Scale.
+func colorScale(c color.Color) (r, g, b, a float64) {
+	ur, ug, ub, ua := c.RGBA()
+	if ua == 0 {
+		return 0, 0, 0, 0
+	}
+	const max = math.MaxUint16
+	a = float64(ua) / max
+	// ColorM is applied to non-premultiplied colors.
+	r = float64(ur) / max / a
+	g = float64(ug) / max / a
+	b = float64(ub) / max / a
+	return
+}
+
+func drawRects(dst, src *ebiten.Image, rects []image.Rectangle, c color.Color) error {
+	r, g, b, a := colorScale(c)
+	if a == 0 {
+		return nil
+	}
+	op := &ebiten.DrawImageOptions{
+		ImageParts: rectsImageParts(rects),
+	}
+	op.ColorM.Scale(r, g, b, a)
+	return dst.DrawImage(src, op)
+}
+
+// circleImageSize is the size of the circle texture.
+// The texture is stretched with linear filter, so the edges are anti-aliased at any size reasonably.
+const circleImageSize = 256
+
+var (
+	circleImage     *ebiten.Image
+	circleImageLock sync.Mutex
+
+	// circleScratchImage is an offscreen image to draw outlined circles.
+	circleScratchImage *ebiten.Image
+)
+
+func ensureCircleImage() (*ebiten.Image, error) {
+	circleImageLock.Lock()
+	defer circleImageLock.Unlock()
+	if circleImage != nil {
+		return circleImage, nil
+	}
+	const size = circleImageSize
+	pix := image.NewRGBA(image.Rect(0, 0, size, size))
+	const r = size/2 - 1
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			dx := float64(i) + 0.5 - size/2
+			dy := float64(j) + 0.5 - size/2
+			// Anti-alias the edge by the coverage approximated by the distance.
+			a := r - math.Sqrt(dx*dx+dy*dy) + 0.5
+			if a <= 0 {
+				continue
+			}
+			if a > 1 {
+				a = 1
+			}
+			v := uint8(a * 0xff)
+			pix.SetRGBA(i, j, color.RGBA{v, v, v, v})
+		}
+	}
+	img, err := ebiten.NewImageFromImage(pix, ebiten.FilterLinear)
+	if err != nil {
+		return nil, err
+	}
+	circleImage = img
+	return circleImage, nil
+}
+
+// FillEllipse fills the ellipse whose center is (cx, cy) and radii are rx and ry on dst with the color clr.
+//
+// The edge of the ellipse is anti-aliased.
+func FillEllipse(dst *ebiten.Image, cx, cy, rx, ry float64, clr color.Color) error {
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+	img, err := ensureCircleImage()
+	if err != nil {
+		return err
+	}
+	r, g, b, a := colorScale(clr)
+	if a == 0 {
+		return nil
+	}
+	op := &ebiten.DrawImageOptions{}
+	// The circle in the texture has 1 pixel margin.
+	const s = circleImageSize/2 - 1
+	op.GeoM.Translate(-circleImageSize/2, -circleImageSize/2)
+	op.GeoM.Scale(rx/s, ry/s)
+	op.GeoM.Translate(cx, cy)
+	op.ColorM.Scale(r, g, b, a)
+	return dst.DrawImage(img, op)
+}
+
+// FillCircle fills the circle whose center is (cx, cy) and radius is radius on dst with the color clr.
+//
+// The edge of the circle is anti-aliased.
+func FillCircle(dst *ebiten.Image, cx, cy, radius float64, clr color.Color) error {
+	return FillEllipse(dst, cx, cy, radius, radius, clr)
+}
+
+type singleImagePart struct {
+	dst image.Rectangle
+	src image.Rectangle
+}
+
+func (s *singleImagePart) Len() int {
+	return 1
+}
+
+func (s *singleImagePart) Dst(i int) (x0, y0, x1, y1 int) {
+	return s.dst.Min.X, s.dst.Min.Y, s.dst.Max.X, s.dst.Max.Y
+}
+
+func (s *singleImagePart) Src(i int) (x0, y0, x1, y1 int) {
+	return s.src.Min.X, s.src.Min.Y, s.src.Max.X, s.src.Max.Y
+}
+
+// DrawCircle draws the outline of the circle whose center is (cx, cy) and radius is radius on dst
+// with the color clr.
+//
+// lineWidth is the width of the outline, and the outline is centered on the circle.
+// The edges of the outline are anti-aliased.
+//
+// DrawCircle uses an offscreen image internally, and is slower than FillCircle.
+func DrawCircle(dst *ebiten.Image, cx, cy, radius, lineWidth float64, clr color.Color) error {
+	if lineWidth <= 0 {
+		return nil
+	}
+	outer := radius + lineWidth/2
+	inner := radius - lineWidth/2
+	if inner <= 0 {
+		return FillCircle(dst, cx, cy, outer, clr)
+	}
+	r, g, b, a := colorScale(clr)
+	if a == 0 {
+		return nil
+	}
+
+	// Draw the ring on the scratch image: fill the outer circle and then cut out the inner circle.
+	// The ring is drawn at the same fractional position as the destination to keep the sub-pixel accuracy.
+	fx, fy := cx-math.Floor(cx), cy-math.Floor(cy)
+	size := int(math.Ceil(2*outer)) + 2
+	circleImageLock.Lock()
+	scratch := circleScratchImage
+	if scratch != nil {
+		if w, h := scratch.Size(); w < size || h < size {
+			_ = scratch.Dispose()
+			scratch = nil
+		}
+	}
+	if scratch == nil {
+		s := 1
+		for s < size {
+			s <<= 1
+		}
+		var err error
+		scratch, err = ebiten.NewImage(s, s, ebiten.FilterNearest)
+		if err != nil {
+			circleImageLock.Unlock()
+			return err
+		}
+		circleScratchImage = scratch
+	}
+	circleImageLock.Unlock()
+
+	if err := scratch.Clear(); err != nil {
+		return err
+	}
+	// h is an integer so that the scratch image is drawn at integer positions.
+	h := float64(size / 2)
+	if err := FillCircle(scratch, h+fx, h+fy, outer, color.White); err != nil {
+		return err
+	}
+	img, err := ensureCircleImage()
+	if err != nil {
+		return err
+	}
+	op := &ebiten.DrawImageOptions{}
+	const s = circleImageSize/2 - 1
+	op.GeoM.Translate(-circleImageSize/2, -circleImageSize/2)
+	op.GeoM.Scale(inner/s, inner/s)
+	op.GeoM.Translate(h+fx, h+fy)
+	op.CompositeMode = ebiten.CompositeModeDestinationOut
+	if err := scratch.DrawImage(img, op); err != nil {
+		return err
+	}
+
+	op = &ebiten.DrawImageOptions{
+		ImageParts: &singleImagePart{
+			dst: image.Rect(0, 0, size, size),
+			src: image.Rect(0, 0, size, size),
+		},
+	}
+	op.GeoM.Translate(math.Floor(cx)-h, math.Floor(cy)-h)
+	op.ColorM.Scale(r, g, b, a)
+	return dst.DrawImage(scratch, op)
+}
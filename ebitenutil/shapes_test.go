@@ -0,0 +1,73 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	. "github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+func TestMain(m *testing.M) {
+	code := 0
+	// Run an Ebiten process so that (*Image).At is available.
+	regularTermination := errors.New("regular termination")
+	f := func(screen *ebiten.Image) error {
+		code = m.Run()
+		return regularTermination
+	}
+	if err := ebiten.Run(f, 320, 240, 1, "Test"); err != nil && err != regularTermination {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+func alphaAt(img *ebiten.Image, x, y int) int {
+	_, _, _, a := img.At(x, y).RGBA()
+	return int(a >> 8)
+}
+
+func TestFillCircleOffCenter(t *testing.T) {
+	const size = 17
+	dst, err := ebiten.NewImage(size, size, ebiten.FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	// The center is at the center of the pixel (8, 8), and the translation to draw the circle is fractional.
+	if err := FillCircle(dst, 8.5, 8.5, 4, color.White); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := alphaAt(dst, 8, 8), 0xff; got != want {
+		t.Errorf("alpha at (8, 8): got %d, want: %d", got, want)
+	}
+	// The circle must not be shifted: the pixels must be symmetric around the center.
+	const tolerance = 2
+	for i := 0; i < size; i++ {
+		a0, a1 := alphaAt(dst, i, 8), alphaAt(dst, size-1-i, 8)
+		if d := a0 - a1; d < -tolerance || tolerance < d {
+			t.Errorf("alpha at (%d, 8) and (%d, 8): got %d and %d, want: the same", i, size-1-i, a0, a1)
+		}
+		a0, a1 = alphaAt(dst, 8, i), alphaAt(dst, 8, size-1-i)
+		if d := a0 - a1; d < -tolerance || tolerance < d {
+			t.Errorf("alpha at (8, %d) and (8, %d): got %d and %d, want: the same", i, size-1-i, a0, a1)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/assets"
+)
+
+// DebugPrintWrapped draws the string str at (x, y) on the image with the color clr,
+// wrapping lines so that each line fits in maxWidth pixels.
+//
+// Lines are wrapped at spaces, and a word longer than maxWidth is broken at any character.
+// Explicit newlines in str are kept.
+//
+// DebugPrintWrapped returns the height of the drawn text in pixels, which is useful e.g. to size a dialog box.
+//
+// DebugPrintWrapped always returns nil error.
+func DebugPrintWrapped(image *ebiten.Image, str string, x, y, maxWidth int, clr color.Color) (int, error) {
+	str = wrapText(str, maxWidth/assets.TextImageCharWidth)
+	defaultDebugPrintState.initIfNeeded()
+	defaultDebugPrintState.drawText(image, str, x, y, clr)
+	return (strings.Count(str, "\n") + 1) * assets.TextImageCharHeight, nil
+}
+
+// wrapText inserts newlines into str so that each line has at most columns characters.
+//
+// Spaces between words and at the start of a paragraph are kept as they are,
+// except for the spaces at a line break, which are dropped.
+func wrapText(str string, columns int) string {
+	if columns < 1 {
+		columns = 1
+	}
+	lines := []string{}
+	for _, paragraph := range strings.Split(str, "\n") {
+		var line []rune
+		rs := []rune(paragraph)
+		for len(rs) > 0 {
+			// Take the next spaces and the word following them.
+			i := 0
+			for i < len(rs) && rs[i] == ' ' {
+				i++
+			}
+			j := i
+			for j < len(rs) && rs[j] != ' ' {
+				j++
+			}
+			spaces, word := rs[:i], rs[i:j]
+			rs = rs[j:]
+
+			if len(line)+len(spaces)+len(word) <= columns {
+				line = append(line, spaces...)
+				line = append(line, word...)
+				continue
+			}
+			if len(word) == 0 {
+				continue
+			}
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			} else {
+				// The spaces at the start of a paragraph are indentation.
+				line = append(line, spaces...)
+			}
+			for len(line)+len(word) > columns {
+				n := columns - len(line)
+				if n <= 0 {
+					lines = append(lines, string(line))
+					line = nil
+					continue
+				}
+				line = append(line, word[:n]...)
+				lines = append(lines, string(line))
+				line = nil
+				word = word[n:]
+			}
+			line = append(line, word...)
+		}
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	cases := []struct {
+		In      string
+		Columns int
+		Out     string
+	}{
+		{"", 5, ""},
+		{"hello world", 11, "hello world"},
+		{"hello world", 5, "hello\nworld"},
+		{"hello world", 8, "hello\nworld"},
+
+		// Newlines
+		{"a\nb c", 3, "a\nb c"},
+		{"a\n\nb", 3, "a\n\nb"},
+		{"abc def\nghi", 5, "abc\ndef\nghi"},
+
+		// Long words
+		{"abcdefghij", 4, "abcd\nefgh\nij"},
+		{"ab cdefghij", 4, "ab\ncdef\nghij"},
+		{"ab", 0, "a\nb"},
+
+		// Multiple spaces and indentation
+		{"a  b", 10, "a  b"},
+		{"a  b   c", 4, "a  b\nc"},
+		{"  indented text", 10, "  indented\ntext"},
+		{"  abcdef", 4, "  ab\ncdef"},
+		{"ab ", 3, "ab "},
+		{"ab   ", 3, "ab"},
+
+		// Non-ASCII
+		{"héllo wörld", 5, "héllo\nwörld"},
+		{"日本語のテキスト", 3, "日本語\nのテキ\nスト"},
+	}
+	for _, c := range cases {
+		if got := wrapText(c.In, c.Columns); got != c.Out {
+			t.Errorf("wrapText(%q, %d): got %q, want: %q", c.In, c.Columns, got, c.Out)
+		}
+	}
+}
@@ -62,13 +62,12 @@ func init() {
 type Player struct {
 	audioPlayer *audio.Player
 	total       time.Duration
-	seekedCh    chan error
 }
 
 var (
 	audioContext     *audio.Context
 	musicPlayer      *Player
-	seBytes          []byte
+	sePool           *audio.PlayerPool
 	musicCh          = make(chan *Player)
 	seCh             = make(chan []byte)
 	mouseButtonState = map[ebiten.MouseButton]int{}
@@ -84,7 +83,7 @@ func playerBarRect() (x, y, w, h int) {
 }
 
 func (p *Player) updateSE() error {
-	if seBytes == nil {
+	if sePool == nil {
 		return nil
 	}
 	if !ebiten.IsKeyPressed(ebiten.KeyP) {
@@ -95,11 +94,9 @@ func (p *Player) updateSE() error {
 	if keyState[ebiten.KeyP] != 1 {
 		return nil
 	}
-	sePlayer, err := audio.NewPlayerFromBytes(audioContext, seBytes)
-	if err != nil {
-		return err
-	}
-	return sePlayer.Play()
+	// The pool reuses the players, and at most 8 SEs are played at once.
+	_, err := sePool.Play()
+	return err
 }
 
 func (p *Player) updateVolume() {
@@ -139,36 +136,34 @@ func (p *Player) updatePlayPause() error {
 	return p.audioPlayer.Play()
 }
 
-func (p *Player) updateBar() {
+func (p *Player) updateBar() error {
 	if p.audioPlayer == nil {
-		return
+		return nil
 	}
-	if p.seekedCh != nil {
-		return
+	if !p.audioPlayer.CanSeek() {
+		// The seek bar doesn't work e.g. for a live stream.
+		return nil
 	}
 	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 		mouseButtonState[ebiten.MouseButtonLeft] = 0
-		return
+		return nil
 	}
 	mouseButtonState[ebiten.MouseButtonLeft]++
 	if mouseButtonState[ebiten.MouseButtonLeft] != 1 {
-		return
+		return nil
 	}
 	x, y := ebiten.CursorPosition()
 	bx, by, bw, bh := playerBarRect()
 	const padding = 4
 	if y < by-padding || by+bh+padding <= y {
-		return
+		return nil
 	}
 	if x < bx || bx+bw <= x {
-		return
+		return nil
 	}
 	pos := time.Duration(x-bx) * p.total / time.Duration(bw)
-	p.seekedCh = make(chan error, 1)
-	go func() {
-		// This can't be done parallely! !?!?
-		p.seekedCh <- p.audioPlayer.Seek(pos)
-	}()
+	// Seek returns immediately, and the source is actually sought at the next audioContext.Update.
+	return p.audioPlayer.Seek(pos)
 }
 
 func (p *Player) close() error {
@@ -182,14 +177,17 @@ func update(screen *ebiten.Image) error {
 		default:
 		}
 	}
-	if seBytes == nil {
+	if sePool == nil {
 		select {
-		case seBytes = <-seCh:
+		case b := <-seCh:
+			sePool = audio.NewPlayerPool(audioContext, b, 8)
 		default:
 		}
 	}
 	if musicPlayer != nil {
-		musicPlayer.updateBar()
+		if err := musicPlayer.updateBar(); err != nil {
+			return err
+		}
 		if err := musicPlayer.updatePlayPause(); err != nil {
 			return err
 		}
@@ -228,17 +226,6 @@ Press Z or X to change volume of the music
 %s`, ebiten.CurrentFPS(), currentTimeStr)
 	if musicPlayer == nil {
 		msg += "\nNow Loading..."
-	} else if musicPlayer.seekedCh != nil {
-		select {
-		case err := <-musicPlayer.seekedCh:
-			if err != nil {
-				return err
-			}
-			close(musicPlayer.seekedCh)
-			musicPlayer.seekedCh = nil
-		default:
-			msg += "\nSeeking..."
-		}
 	}
 	ebitenutil.DebugPrint(screen, msg)
 	if err := audioContext.Update(); err != nil {
@@ -256,11 +243,9 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	// This sample rate doesn't match with wav/ogg's sample rate,
+	// The platform's sample rate doesn't always match with wav/ogg's sample rate,
 	// but decoders adjust them.
-	const sampleRate = 48000
-	const bytesPerSample = 4 // TODO: This should be defined in audio package
-	audioContext, err = audio.NewContext(sampleRate)
+	audioContext, err = audio.NewContextDefault()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -279,7 +264,8 @@ func main() {
 		close(seCh)
 	}()
 	go func() {
-		s, err := vorbis.Decode(audioContext, oggF)
+		// Decode the music lazily so that the whole decoded data is not kept on memory.
+		s, err := vorbis.DecodeStream(audioContext, oggF)
 		if err != nil {
 			log.Fatal(err)
 			return
@@ -291,7 +277,7 @@ func main() {
 		}
 		musicCh <- &Player{
 			audioPlayer: p,
-			total:       time.Second * time.Duration(s.Size()) / bytesPerSample / sampleRate,
+			total:       p.Length(),
 		}
 		close(musicCh)
 		// TODO: Is this goroutine-safe?
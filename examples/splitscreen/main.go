@@ -0,0 +1,107 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example
+
+package main
+
+import (
+	"image"
+	_ "image/jpeg"
+	"log"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+const (
+	screenWidth  = 320
+	screenHeight = 240
+)
+
+var (
+	gophersImage *ebiten.Image
+)
+
+type camera struct {
+	x, y                  float64
+	up, down, left, right ebiten.Key
+}
+
+var cameras = []*camera{
+	{0, 0, ebiten.KeyW, ebiten.KeyS, ebiten.KeyA, ebiten.KeyD},
+	{0, 0, ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyLeft, ebiten.KeyRight},
+}
+
+func (c *camera) update() {
+	const speed = 2
+	if ebiten.IsKeyPressed(c.up) {
+		c.y -= speed
+	}
+	if ebiten.IsKeyPressed(c.down) {
+		c.y += speed
+	}
+	if ebiten.IsKeyPressed(c.left) {
+		c.x -= speed
+	}
+	if ebiten.IsKeyPressed(c.right) {
+		c.x += speed
+	}
+}
+
+func drawWorld(screen *ebiten.Image) {
+	// The world is the gophers image tiled 3x3.
+	w, h := gophersImage.Size()
+	for j := -1; j <= 1; j++ {
+		for i := -1; i <= 1; i++ {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(i*w)-float64(w)/2, float64(j*h)-float64(h)/2)
+			screen.DrawImage(gophersImage, op)
+		}
+	}
+}
+
+func update(screen *ebiten.Image) error {
+	for _, c := range cameras {
+		c.update()
+	}
+	if ebiten.IsRunningSlowly() {
+		return nil
+	}
+
+	viewWidth := screenWidth / len(cameras)
+	for i, c := range cameras {
+		// Restrict the drawing to the region of the view.
+		screen.SetClipRect(image.Rect(i*viewWidth, 0, (i+1)*viewWidth, screenHeight))
+		// The camera position is at the center of the view.
+		screen.PushViewMatrix(ebiten.TranslateGeo(-c.x+float64(i*viewWidth+viewWidth/2), -c.y+screenHeight/2))
+		drawWorld(screen)
+		screen.PopViewMatrix()
+	}
+	screen.ResetClipRect()
+
+	ebitenutil.DebugPrint(screen, "WASD: Move the left view\nArrow keys: Move the right view")
+	return nil
+}
+
+func main() {
+	var err error
+	gophersImage, _, err = ebitenutil.NewImageFromFile("_resources/images/gophers.jpg", ebiten.FilterNearest)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Split Screen (Ebiten Demo)"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -126,8 +126,9 @@ func update(screen *ebiten.Image) error {
 	op.ColorM.Scale(1.0, 1.0, 1.0, 0.5)
 	screen.DrawImage(ebitenImage, op)
 	msg := fmt.Sprintf(`FPS: %0.2f
+Draw calls: %d
 Num of sprites: %d
-Press <- or -> to change the number of sprites`, ebiten.CurrentFPS(), sprites.Len())
+Press <- or -> to change the number of sprites`, ebiten.CurrentFPS(), ebiten.DrawCallCount(), sprites.Len())
 	ebitenutil.DebugPrint(screen, msg)
 	return nil
 }
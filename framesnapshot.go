@@ -0,0 +1,220 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"errors"
+	"image"
+)
+
+// FrameSnapshot is a snapshot of the screen and the input states at a frame.
+//
+// FrameSnapshot is useful e.g. for a frame-stepping debugger or regression tests of rendering
+// with deterministic replays: capture snapshots at frames, and compare them with the ones captured later.
+//
+// All the fields are plain data: a snapshot can be persisted e.g. by encoding/gob or encoding/json,
+// and Screen can be saved as a PNG file by image/png.
+type FrameSnapshot struct {
+	// Screen is the pixels of the screen image. The pixel format is alpha-premultiplied.
+	Screen *image.RGBA
+
+	// PressedKeys is the pressed keys in ascending order.
+	PressedKeys []Key
+
+	// PressedMouseButtons is the pressed mouse buttons in ascending order.
+	PressedMouseButtons []MouseButton
+
+	// CursorX and CursorY are the cursor position in the screen coordinates.
+	CursorX int
+	CursorY int
+
+	// Touches is the touch states.
+	Touches []TouchSnapshot
+
+	// Gamepads is the gamepad states in the order of GamepadIDs.
+	Gamepads []GamepadSnapshot
+}
+
+// TouchSnapshot is a snapshot of a touch.
+type TouchSnapshot struct {
+	ID int
+	X  int
+	Y  int
+}
+
+// GamepadSnapshot is a snapshot of a gamepad.
+type GamepadSnapshot struct {
+	ID int
+
+	// Axes is the values of the axes after the dead zone is applied (see GamepadAxis).
+	Axes []float64
+
+	// PressedButtons is the pressed buttons in ascending order.
+	PressedButtons []GamepadButton
+}
+
+// CaptureFrame captures the current screen pixels and input states.
+//
+// CaptureFrame must be called in the game function passed to Run. The screen pixels are the ones drawn
+// to the screen image so far at the current frame, so call CaptureFrame after drawing.
+//
+// CaptureFrame reads pixels from VRAM, and this is a slow operation.
+//
+// CaptureFrame returns error when it is called out of the main loop or reading the pixels fails.
+func CaptureFrame() (*FrameSnapshot, error) {
+	g, ok := theGraphicsContext.Load().(*graphicsContext)
+	if !ok || g == nil || g.offscreen == nil {
+		return nil, errors.New("ebiten: CaptureFrame must be called in the game function")
+	}
+	screen, err := g.offscreen.ToImage()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FrameSnapshot{
+		Screen:      screen,
+		PressedKeys: PressedKeys(),
+	}
+	for _, b := range []MouseButton{MouseButtonLeft, MouseButtonRight, MouseButtonMiddle} {
+		if IsMouseButtonPressed(b) {
+			s.PressedMouseButtons = append(s.PressedMouseButtons, b)
+		}
+	}
+	s.CursorX, s.CursorY = CursorPosition()
+	for _, t := range Touches() {
+		x, y := t.Position()
+		s.Touches = append(s.Touches, TouchSnapshot{
+			ID: t.ID(),
+			X:  x,
+			Y:  y,
+		})
+	}
+	for _, id := range GamepadIDs() {
+		gp := GamepadSnapshot{
+			ID: id,
+		}
+		for a := 0; a < GamepadAxisNum(id); a++ {
+			gp.Axes = append(gp.Axes, GamepadAxis(id, a))
+		}
+		for b := 0; b < GamepadButtonNum(id); b++ {
+			if IsGamepadButtonPressed(id, GamepadButton(b)) {
+				gp.PressedButtons = append(gp.PressedButtons, GamepadButton(b))
+			}
+		}
+		s.Gamepads = append(s.Gamepads, gp)
+	}
+	return s, nil
+}
+
+// Equal returns a boolean value indicating whether the snapshot has exactly the same screen pixels and
+// input states as other.
+func (s *FrameSnapshot) Equal(other *FrameSnapshot) bool {
+	return s.ScreenDiff(other) == 0 && s.InputEqual(other)
+}
+
+// ScreenDiff returns the number of the pixels that are different between the screens of the snapshots.
+//
+// If the screen sizes are different, the pixels out of the common area are counted as different.
+func (s *FrameSnapshot) ScreenDiff(other *FrameSnapshot) int {
+	b0 := image.Rectangle{}
+	if s.Screen != nil {
+		b0 = s.Screen.Bounds()
+	}
+	b1 := image.Rectangle{}
+	if other.Screen != nil {
+		b1 = other.Screen.Bounds()
+	}
+	w0, h0 := b0.Dx(), b0.Dy()
+	w1, h1 := b1.Dx(), b1.Dy()
+	w, h := w0, h0
+	if w1 < w {
+		w = w1
+	}
+	if h1 < h {
+		h = h1
+	}
+	n := w0*h0 + w1*h1 - 2*w*h
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			if s.Screen.RGBAAt(b0.Min.X+i, b0.Min.Y+j) != other.Screen.RGBAAt(b1.Min.X+i, b1.Min.Y+j) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// InputEqual returns a boolean value indicating whether the snapshot has the same input states as other.
+func (s *FrameSnapshot) InputEqual(other *FrameSnapshot) bool {
+	if s.CursorX != other.CursorX || s.CursorY != other.CursorY {
+		return false
+	}
+	if len(s.PressedKeys) != len(other.PressedKeys) {
+		return false
+	}
+	for i, k := range s.PressedKeys {
+		if other.PressedKeys[i] != k {
+			return false
+		}
+	}
+	if len(s.PressedMouseButtons) != len(other.PressedMouseButtons) {
+		return false
+	}
+	for i, b := range s.PressedMouseButtons {
+		if other.PressedMouseButtons[i] != b {
+			return false
+		}
+	}
+	if len(s.Touches) != len(other.Touches) {
+		return false
+	}
+	for i, t := range s.Touches {
+		if other.Touches[i] != t {
+			return false
+		}
+	}
+	if len(s.Gamepads) != len(other.Gamepads) {
+		return false
+	}
+	for i, g := range s.Gamepads {
+		if !g.equal(&other.Gamepads[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *GamepadSnapshot) equal(other *GamepadSnapshot) bool {
+	if g.ID != other.ID {
+		return false
+	}
+	if len(g.Axes) != len(other.Axes) {
+		return false
+	}
+	for i, a := range g.Axes {
+		if other.Axes[i] != a {
+			return false
+		}
+	}
+	if len(g.PressedButtons) != len(other.PressedButtons) {
+		return false
+	}
+	for i, b := range g.PressedButtons {
+		if other.PressedButtons[i] != b {
+			return false
+		}
+	}
+	return true
+}
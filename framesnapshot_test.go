@@ -0,0 +1,59 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestFrameSnapshotCompare(t *testing.T) {
+	s0 := &FrameSnapshot{
+		Screen:      image.NewRGBA(image.Rect(0, 0, 4, 4)),
+		PressedKeys: []Key{KeyA},
+		CursorX:     1,
+		CursorY:     2,
+	}
+	s1 := &FrameSnapshot{
+		Screen:      image.NewRGBA(image.Rect(0, 0, 4, 4)),
+		PressedKeys: []Key{KeyA},
+		CursorX:     1,
+		CursorY:     2,
+	}
+	if !s0.Equal(s1) {
+		t.Errorf("s0.Equal(s1): got false, want: true")
+	}
+
+	s1.Screen.SetRGBA(1, 1, color.RGBA{0xff, 0, 0, 0xff})
+	if got, want := s0.ScreenDiff(s1), 1; got != want {
+		t.Errorf("s0.ScreenDiff(s1): got %d, want: %d", got, want)
+	}
+	if s0.Equal(s1) {
+		t.Errorf("s0.Equal(s1): got true, want: false")
+	}
+
+	s1.Screen = image.NewRGBA(image.Rect(0, 0, 4, 5))
+	if got, want := s0.ScreenDiff(s1), 4; got != want {
+		t.Errorf("s0.ScreenDiff(s1) with a different size: got %d, want: %d", got, want)
+	}
+
+	s1.PressedKeys = []Key{KeyB}
+	if s0.InputEqual(s1) {
+		t.Errorf("s0.InputEqual(s1): got true, want: false")
+	}
+}
@@ -28,9 +28,16 @@ type GeoM struct {
 	impl affine.GeoM
 }
 
+// Reset resets the GeoM as identity.
+//
+// Reset doesn't allocate memory. This is useful to reuse a DrawImageOptions for many draw calls.
+func (g *GeoM) Reset() {
+	g.impl.Reset()
+}
+
 // Element returns a value of a matrix at (i, j).
 func (g *GeoM) Element(i, j int) float64 {
-	return g.impl.UnsafeElements()[i*affine.GeoMDim+j]
+	return g.impl.Elements()[i*affine.GeoMDim+j]
 }
 
 // Concat multiplies a geometry matrix with the other geometry matrix.
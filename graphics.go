@@ -15,6 +15,8 @@
 package ebiten
 
 import (
+	"fmt"
+
 	"github.com/hajimehoshi/ebiten/internal/opengl"
 )
 
@@ -36,7 +38,14 @@ func glFilter(filter Filter) opengl.Filter {
 	case FilterLinear:
 		return opengl.Linear
 	}
-	panic("not reach")
+	panic(fmt.Sprintf("ebiten: invalid filter: %d", filter))
+}
+
+// glFilters returns the OpenGL filters for minification and magnification.
+//
+// If min or mag is not a valid value, glFilters panics.
+func glFilters(min, mag Filter) (opengl.Filter, opengl.Filter) {
+	return glFilter(min), glFilter(mag)
 }
 
 // CompositeMode represents Porter-Duff composition mode.
@@ -17,6 +17,7 @@ package ebiten
 import (
 	"math"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/internal/graphics"
 	"github.com/hajimehoshi/ebiten/internal/opengl"
@@ -38,6 +39,12 @@ type graphicsContext struct {
 	screenScale float64
 	initialized int32
 	invalidated bool
+	updated     bool
+
+	// lastUpdateDuration, lastDrawDuration and lastDrawEnd are used for the profile callback.
+	lastUpdateDuration time.Duration
+	lastDrawDuration   time.Duration
+	lastDrawEnd        time.Time
 }
 
 func (c *graphicsContext) GLContext() *opengl.Context {
@@ -55,7 +62,7 @@ func (c *graphicsContext) Invalidate() {
 	c.invalidated = true
 }
 
-func (c *graphicsContext) SetSize(screenWidth, screenHeight int, screenScale float64) error {
+func (c *graphicsContext) SetSize(screenWidth, screenHeight int, screenScale float64, pixelAspectRatio float64) error {
 	if c.screen != nil {
 		if err := c.screen.Dispose(); err != nil {
 			return err
@@ -76,21 +83,22 @@ func (c *graphicsContext) SetSize(screenWidth, screenHeight int, screenScale flo
 		return err
 	}
 
-	intScreenScale := int(math.Ceil(screenScale))
-	w := screenWidth * intScreenScale
-	h := screenHeight * intScreenScale
+	// The horizontal scale is different from the vertical scale when the pixel aspect ratio is not 1.
+	screenScaleX := screenScale * pixelAspectRatio
+	w := screenWidth * int(math.Ceil(screenScaleX))
+	h := screenHeight * int(math.Ceil(screenScale))
 	offscreen2, err := newVolatileImage(w, h, FilterLinear)
 	if err != nil {
 		return err
 	}
 
-	w = int(float64(screenWidth) * screenScale)
+	w = int(float64(screenWidth) * screenScaleX)
 	h = int(float64(screenHeight) * screenScale)
 	c.screen, err = newImageWithScreenFramebuffer(w, h)
 	if err != nil {
 		return err
 	}
-	if err := c.screen.Clear(); err != nil {
+	if err := c.screen.Fill(borderColor()); err != nil {
 		return err
 	}
 
@@ -117,6 +125,7 @@ func (c *graphicsContext) initializeIfNeeded(context *opengl.Context) error {
 			return err
 		}
 		atomic.StoreInt32(&c.initialized, 1)
+		callGraphicsReadyCallback()
 	}
 	r, err := c.needsRestoring(context)
 	if err != nil {
@@ -145,7 +154,7 @@ func drawWithFittingScale(dst *Image, src *Image) error {
 }
 
 func (c *graphicsContext) drawToDefaultRenderTarget(context *opengl.Context) error {
-	if err := c.screen.Clear(); err != nil {
+	if err := c.screen.Fill(borderColor()); err != nil {
 		return err
 	}
 	if err := drawWithFittingScale(c.screen, c.offscreen2); err != nil {
@@ -158,19 +167,34 @@ func (c *graphicsContext) drawToDefaultRenderTarget(context *opengl.Context) err
 }
 
 func (c *graphicsContext) UpdateAndDraw(context *opengl.Context, updateCount int) error {
+	start := time.Now()
+	if !c.lastDrawEnd.IsZero() {
+		callProfileCallback(c.lastUpdateDuration, c.lastDrawDuration, start.Sub(c.lastDrawEnd))
+	}
 	if err := c.initializeIfNeeded(context); err != nil {
 		return err
 	}
 	if err := restorable.ResolveStalePixels(context); err != nil {
 		return err
 	}
+	updateStart := time.Now()
 	for i := 0; i < updateCount; i++ {
-		restorable.ClearVolatileImages()
+		// When the screen is not cleared every frame, only the offscreen keeps its contents.
+		// The other volatile images like offscreen2 and the user's float images are cleared as usual.
+		var except *restorable.Image
+		if !IsScreenClearedEveryFrame() {
+			except = c.offscreen.restorable
+		}
+		restorable.ClearVolatileImages(except)
 		setRunningSlowly(i < updateCount-1)
+		theKeyDurations.update()
+		setFirstUpdate(!c.updated)
+		c.updated = true
 		if err := c.f(c.offscreen); err != nil {
 			return err
 		}
 	}
+	drawStart := time.Now()
 	if 0 < updateCount {
 		if err := drawWithFittingScale(c.offscreen2, c.offscreen); err != nil {
 			return err
@@ -179,6 +203,10 @@ func (c *graphicsContext) UpdateAndDraw(context *opengl.Context, updateCount int
 	if err := c.drawToDefaultRenderTarget(context); err != nil {
 		return err
 	}
+	setDrawCallCount(graphics.TakeDrawCallCount())
+	c.lastDrawEnd = time.Now()
+	c.lastUpdateDuration = drawStart.Sub(updateStart)
+	c.lastDrawDuration = c.lastDrawEnd.Sub(drawStart)
 	return nil
 }
 
@@ -0,0 +1,68 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// GraphicsInfo represents the information of the graphics driver.
+type GraphicsInfo struct {
+	// Version is the version string of OpenGL, OpenGL ES or WebGL.
+	Version string
+
+	// Renderer is the name of the renderer (typically the GPU).
+	Renderer string
+
+	// NPOT reports whether textures whose sizes are not powers of 2 are fully supported.
+	NPOT bool
+
+	// FramebufferObject reports whether framebuffer objects are supported.
+	FramebufferObject bool
+
+	// Anisotropy reports whether anisotropic texture filtering is supported.
+	Anisotropy bool
+
+	// FloatTexture reports whether floating point render targets are supported.
+	FloatTexture bool
+
+	// MaxTextureSize is the maximum width/height of a texture reported by the graphics driver.
+	// The width/height of an image is limited by the smaller of MaxTextureSize and MaxImageSize.
+	MaxTextureSize int
+}
+
+// CurrentGraphicsInfo returns the information of the graphics driver.
+//
+// The information is available after the game starts.
+// Before that, CurrentGraphicsInfo returns nil.
+//
+// Note that Ebiten requires OpenGL 2.1 or later with framebuffer objects on desktops,
+// and Run returns an error at startup when the environment doesn't satisfy the requirement.
+func CurrentGraphicsInfo() *GraphicsInfo {
+	c := glContext()
+	if c == nil {
+		return nil
+	}
+	i := c.Info()
+	if i.Version == "" {
+		// The context is not initialized yet.
+		return nil
+	}
+	return &GraphicsInfo{
+		Version:           i.Version,
+		Renderer:          i.Renderer,
+		NPOT:              i.NPOT,
+		FramebufferObject: i.FramebufferObject,
+		Anisotropy:        i.Anisotropy,
+		FloatTexture:      i.FloatTexture,
+		MaxTextureSize:    i.MaxTextureSize,
+	}
+}
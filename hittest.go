@@ -0,0 +1,78 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"math"
+)
+
+// ImageContains returns a boolean value indicating whether the point (x, y) is inside
+// the image src drawn with the options op.
+//
+// The point is in the destination coordinates, e.g. the cursor position for the screen.
+// The point is transformed by the inverse of the geometry matrix that op represents
+// (including OriginX, OriginY, Rotation, X and Y) and is checked against the bounds of src,
+// or against the size of SourceRect if SourceRect is set.
+// ImageParts and the view matrices of the destination image are not taken into account.
+// op can be nil.
+//
+// If the geometry matrix is not invertible (e.g. scaled by 0), ImageContains returns false.
+//
+// This is useful e.g. to check whether the user clicks a sprite.
+func ImageContains(op *DrawImageOptions, src *Image, x, y int) bool {
+	_, _, ok := imageContains(op, src, x, y)
+	return ok
+}
+
+// ImageContainsOpaque is like ImageContains, but additionally returns false
+// when the pixel of src at the point is fully transparent.
+//
+// This is useful for pixel-perfect hit testing of sprites with transparent areas.
+// As ImageContainsOpaque reads a pixel of src, this is slower than ImageContains.
+//
+// This method can't be called before the main loop (ebiten.Run) starts.
+func ImageContainsOpaque(op *DrawImageOptions, src *Image, x, y int) bool {
+	sx, sy, ok := imageContains(op, src, x, y)
+	if !ok {
+		return false
+	}
+	_, _, _, a := src.At(sx, sy).RGBA()
+	return a > 0
+}
+
+func imageContains(op *DrawImageOptions, src *Image, x, y int) (sx, sy int, ok bool) {
+	if op == nil {
+		op = &DrawImageOptions{}
+	}
+	g := op.geoM()
+	if op.PixelSnapping {
+		g.impl.RoundTranslation()
+	}
+	// Use the center of the pixel.
+	fx, fy, ok := g.impl.InverseApply(float64(x)+0.5, float64(y)+0.5)
+	if !ok {
+		return 0, 0, false
+	}
+	sx, sy = int(math.Floor(fx)), int(math.Floor(fy))
+	r := src.Bounds()
+	// SourceRect is ignored when ImageParts or Parts is set as DrawImage does.
+	if op.ImageParts == nil && op.Parts == nil && op.SourceRect != nil {
+		r = *op.SourceRect
+	}
+	if sx < 0 || sy < 0 || r.Dx() <= sx || r.Dy() <= sy {
+		return 0, 0, false
+	}
+	return r.Min.X + sx, r.Min.Y + sy, true
+}
@@ -15,6 +15,7 @@
 package ebiten
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -44,6 +45,50 @@ func glContext() *opengl.Context {
 // Functions of Image never returns error as of 1.5.0-alpha, and error values are always nil.
 type Image struct {
 	restorable *restorable.Image
+	minFilter  Filter
+	magFilter  Filter
+
+	// viewMatrices is a stack of view matrices. Each element is already multiplied by its parents.
+	viewMatrices []GeoM
+
+	clipRect image.Rectangle
+	clipped  bool
+
+	// trackingID is the ID for the leak detection. 0 means the image is not tracked.
+	trackingID int
+}
+
+// Filter returns the filter of the image.
+//
+// If the image has different filters for minification and magnification (see NewImageWithFilters),
+// Filter returns the filter for magnification. Use Filters to get both.
+//
+// The screen image's filter is FilterNearest.
+func (i *Image) Filter() Filter {
+	return i.magFilter
+}
+
+// Filters returns the filters of the image for minification and magnification.
+func (i *Image) Filters() (min, mag Filter) {
+	return i.minFilter, i.magFilter
+}
+
+// SetFilter changes the filter of the image both for minification and magnification.
+//
+// The pixels of the image are kept as they are, and only the way the image is sampled changes.
+// This is useful e.g. to toggle smoothing at runtime.
+//
+// If filter is not a valid value, SetFilter panics.
+//
+// When the image is disposed, SetFilter does nothing.
+func (i *Image) SetFilter(filter Filter) {
+	min, mag := glFilters(filter, filter)
+	if i.restorable == nil {
+		return
+	}
+	i.restorable.SetFilter(min, mag)
+	i.minFilter = filter
+	i.magFilter = filter
 }
 
 // Size returns the size of the image.
@@ -63,11 +108,27 @@ func (i *Image) Clear() error {
 
 // Fill fills the image with a solid color.
 //
+// Fill replaces all the pixels with the color without blending.
+// clr is interpreted as same as the standard image/color package:
+// color.RGBA is alpha-premultiplied, and color.NRGBA is non-alpha-premultiplied (straight).
+// For example, both color.RGBA{0, 0, 0, 0x80} and color.NRGBA{0, 0, 0, 0x80} represent 50% black,
+// and drawing an image filled with it works as a 50% dimmer.
+// As a premultiplied color's components can't exceed its alpha, such components of clr are clamped to the alpha.
+//
 // When the image is disposed, Fill does nothing.
 //
 // Fill always returns nil as of 1.5.0-alpha.
 func (i *Image) Fill(clr color.Color) error {
 	rgba := color.RGBAModel.Convert(clr).(color.RGBA)
+	if rgba.R > rgba.A {
+		rgba.R = rgba.A
+	}
+	if rgba.G > rgba.A {
+		rgba.G = rgba.A
+	}
+	if rgba.B > rgba.A {
+		rgba.B = rgba.A
+	}
 	i.restorable.Fill(rgba)
 	return nil
 }
@@ -84,11 +145,16 @@ func (i *Image) Fill(clr color.Color) error {
 //     GeoM:          Identity matrix
 //     ColorM:        Identity matrix (that changes no colors)
 //     CompositeMode: CompositeModeSourceOver (regular alpha blending)
+//     CornerColors:  White (that changes no colors)
 //
 // For drawing, the pixels of the argument image at the time of this call is adopted.
 // Even if the argument image is mutated after this call,
 // the drawing result is never affected.
 //
+// DrawImage doesn't retain options, and options can be reused for following DrawImage calls.
+// Allocating a DrawImageOptions for every call causes GC pressure especially when there are many sprites.
+// Instead, reuse one DrawImageOptions and reset its members (e.g. by GeoM.Reset and ColorM.Reset).
+//
 // When the image is disposed, DrawImage does nothing.
 //
 // When image is as same as i, DrawImage panics.
@@ -109,24 +175,152 @@ func (i *Image) DrawImage(image *Image, options *DrawImageOptions) error {
 		dparts := options.Parts
 		if dparts != nil {
 			parts = imageParts(dparts)
+		} else if r := options.SourceRect; r != nil {
+			if !r.In(image.Bounds()) {
+				panic(fmt.Sprintf("ebiten: DrawImageOptions.SourceRect %v must be in the image bounds %v", *r, image.Bounds()))
+			}
+			parts = &sourceRect{*r}
 		} else {
 			w, h := image.restorable.Size()
 			parts = &wholeImage{w, h}
 		}
 	}
-	w, h := image.restorable.Size()
-	vs := vertices(parts, w, h, &options.GeoM.impl)
+	geom := options.geoM()
+	if n := len(i.viewMatrices); n > 0 {
+		geom.Concat(i.viewMatrices[n-1])
+	}
+	if options.PixelSnapping {
+		geom.impl.RoundTranslation()
+	}
+	tw, th := image.restorable.TextureSize()
+	vs := vertices(parts, tw, th, &geom.impl, vertexColors(&options.CornerColors))
 	if len(vs) == 0 {
 		return nil
 	}
 	if i == image {
 		panic("ebiten: Image.DrawImage: image must be different from the receiver")
 	}
+	// When i is not clipped, clip is empty and this means no clipping.
+	clip := i.clipRect
+	if i.clipped {
+		clip = clip.Intersect(i.Bounds())
+		if clip.Empty() {
+			return nil
+		}
+	}
+	colorm := options.ColorM.impl
+	if t := clampTransparency(options.Transparency); t != 0 {
+		// affine.ColorM.Scale doesn't modify the original elements.
+		colorm.Scale(1, 1, 1, 1-t)
+	}
 	mode := opengl.CompositeMode(options.CompositeMode)
-	i.restorable.DrawImage(image.restorable, vs, options.ColorM.impl, mode)
+	i.restorable.DrawImage(image.restorable, vs, colorm, mode, clip)
 	return nil
 }
 
+// clampTransparency clamps t into [0, 1]. NaN is regarded as 0.
+func clampTransparency(t float64) float64 {
+	// The condition must be true when t is NaN.
+	if !(0 <= t) {
+		return 0
+	}
+	if t > 1 {
+		return 1
+	}
+	return t
+}
+
+// DrawAt draws the whole given image at each of the given positions on the receiver image.
+//
+// DrawAt is equivalent to calling DrawImage for each position with the translation
+// by the position, but all the images are drawn as one batch. This is much more efficient
+// to draw the same image at many positions, e.g. particles or stars.
+//
+// options works as same as DrawImage except that options.ImageParts and options.Parts are ignored.
+// options.GeoM is applied after the translation by each position.
+// options can be nil.
+//
+// When the image is disposed, DrawAt does nothing.
+//
+// When src is as same as i, DrawAt panics.
+//
+// DrawAt always returns nil as of 1.5.0-alpha.
+func (i *Image) DrawAt(src *Image, positions []image.Point, options *DrawImageOptions) error {
+	if i.restorable == nil || src.restorable == nil {
+		return nil
+	}
+	op := &DrawImageOptions{}
+	if options != nil {
+		*op = *options
+	}
+	w, h := src.restorable.Size()
+	op.ImageParts = &positionedImageParts{positions, w, h}
+	op.Parts = nil
+	return i.DrawImage(src, op)
+}
+
+// PushViewMatrix pushes the view matrix to the image's view matrix stack.
+//
+// The view matrix is applied after GeoM of DrawImageOptions at every DrawImage call on the image.
+// This is useful e.g. to draw objects in world coordinates with a camera.
+// When the stack already has matrices, geom is applied first and then the current view matrix is applied,
+// so that nested coordinate systems can be represented.
+//
+// The view matrix stack is kept across frames. Call PopViewMatrix the same number of times as PushViewMatrix.
+//
+// This function is not concurrent-safe.
+func (i *Image) PushViewMatrix(geom GeoM) {
+	if n := len(i.viewMatrices); n > 0 {
+		geom.Concat(i.viewMatrices[n-1])
+	}
+	i.viewMatrices = append(i.viewMatrices, geom)
+}
+
+// PopViewMatrix pops the view matrix pushed by PushViewMatrix.
+//
+// When the view matrix stack is empty, PopViewMatrix panics.
+//
+// This function is not concurrent-safe.
+func (i *Image) PopViewMatrix() {
+	n := len(i.viewMatrices)
+	if n == 0 {
+		panic("ebiten: Image.PopViewMatrix: the view matrix stack is empty")
+	}
+	i.viewMatrices = i.viewMatrices[:n-1]
+}
+
+// ViewMatrix returns the current view matrix of the image.
+// When the view matrix stack is empty, ViewMatrix returns the identity matrix.
+//
+// This function is not concurrent-safe.
+func (i *Image) ViewMatrix() GeoM {
+	if n := len(i.viewMatrices); n > 0 {
+		return i.viewMatrices[n-1]
+	}
+	return GeoM{}
+}
+
+// SetClipRect restricts the region of the image affected by DrawImage to rect.
+//
+// rect is in the image's pixel coordinates, and is not affected by the view matrix.
+// This is useful e.g. to render multiple views into regions of the screen (split-screen).
+//
+// Clear, Fill and ReplacePixels are not affected by the clipping rectangle.
+//
+// This function is not concurrent-safe.
+func (i *Image) SetClipRect(rect image.Rectangle) {
+	i.clipRect = rect
+	i.clipped = true
+}
+
+// ResetClipRect removes the clipping rectangle set by SetClipRect.
+//
+// This function is not concurrent-safe.
+func (i *Image) ResetClipRect() {
+	i.clipRect = image.Rectangle{}
+	i.clipped = false
+}
+
 // Bounds returns the bounds of the image.
 func (i *Image) Bounds() image.Rectangle {
 	w, h := i.restorable.Size()
@@ -155,6 +349,135 @@ func (i *Image) At(x, y int) color.Color {
 	return clr
 }
 
+// ToImage returns a copy of the image's pixels as an *image.RGBA.
+//
+// The pixel format of the returned image is alpha-premultiplied as well as Image.
+// Use ToNRGBAImage to get non-alpha-premultiplied pixels.
+// This is useful e.g. to save a generated image or to inspect a render target in tests.
+//
+// ToImage loads pixels from VRAM to system memory if necessary, and this is a slow operation.
+// Avoid calling ToImage every frame.
+//
+// This method can't be called before the main loop (ebiten.Run) starts.
+func (i *Image) ToImage() (*image.RGBA, error) {
+	if i.restorable == nil {
+		return nil, errors.New("ebiten: the image is already disposed")
+	}
+	pix, err := i.restorable.Pixels(glContext())
+	if err != nil {
+		return nil, err
+	}
+	w, h := i.restorable.Size()
+	w2, _ := i.restorable.TextureSize()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		copy(img.Pix[j*img.Stride:], pix[j*w2*4:j*w2*4+w*4])
+	}
+	return img, nil
+}
+
+// ToNRGBAImage returns a copy of the image's pixels as an *image.NRGBA.
+//
+// Unlike ToImage, the pixel format of the returned image is non-alpha-premultiplied (straight alpha):
+// the RGB values are divided by the alpha values. The RGB values of fully transparent pixels are 0.
+// This is useful e.g. to process the pixels with tools expecting straight alpha.
+//
+// Note that the precision of translucent pixels' colors is limited by the premultiplied pixels on VRAM.
+//
+// The same notes as ToImage are applied.
+func (i *Image) ToNRGBAImage() (*image.NRGBA, error) {
+	src, err := i.ToImage()
+	if err != nil {
+		return nil, err
+	}
+	b := src.Bounds()
+	img := image.NewNRGBA(b)
+	for idx := 0; idx < len(src.Pix); idx += 4 {
+		a := src.Pix[idx+3]
+		if a == 0 {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			// Round to the nearest value. The result never exceeds 0xff for valid premultiplied colors.
+			v := (int(src.Pix[idx+k])*0xff + int(a)/2) / int(a)
+			if v > 0xff {
+				v = 0xff
+			}
+			img.Pix[idx+k] = uint8(v)
+		}
+		img.Pix[idx+3] = a
+	}
+	return img, nil
+}
+
+// SubPixels returns the pixels in the region r of the image as a byte slice.
+//
+// r is clamped to the image bounds. The returned slice has 4 * r.Dx() * r.Dy() bytes
+// for the clamped r, and represents RGBA pre-multiplied alpha values row by row.
+// If the clamped r is empty, SubPixels returns an empty slice.
+//
+// Unlike At or ToImage, SubPixels reads only the region from VRAM.
+// Even so, reading from VRAM requires a roundtrip to GPU that flushes all enqueued drawing commands
+// and waits for them to finish. This is cheap enough for occasional use like checking a few pixels
+// for collision, but avoid calling SubPixels many times every frame.
+//
+// This method can't be called before the main loop (ebiten.Run) starts.
+func (i *Image) SubPixels(r image.Rectangle) ([]uint8, error) {
+	return i.PixelsRect(r.Intersect(i.Bounds()))
+}
+
+// PixelsRect returns the pixels in the region r of the image as a byte slice.
+//
+// PixelsRect is the same as SubPixels except that r is not clamped:
+// r must be in the image bounds. Otherwise, PixelsRect returns an error.
+// The returned slice has 4 * r.Dx() * r.Dy() bytes, and represents RGBA pre-multiplied alpha values row by row.
+// If r is empty, PixelsRect returns an empty slice.
+//
+// This method can't be called before the main loop (ebiten.Run) starts.
+func (i *Image) PixelsRect(r image.Rectangle) ([]uint8, error) {
+	if i.restorable == nil {
+		return nil, errors.New("ebiten: the image is already disposed")
+	}
+	if !r.In(i.Bounds()) {
+		return nil, fmt.Errorf("ebiten: region %v is out of the image bounds %v", r, i.Bounds())
+	}
+	if r.Empty() {
+		return []uint8{}, nil
+	}
+	return i.restorable.SubPixels(r, glContext())
+}
+
+// Scale returns a new image with the given size, rendering the whole receiver image scaled to fit the size.
+//
+// This is useful e.g. to generate a pre-scaled copy like a small icon once.
+// The receiver image is sampled with the filter specified when the receiver image was created, and
+// filter is used for the returned image.
+//
+// Scale allocates a new texture every time. Avoid calling Scale every frame;
+// use DrawImage with GeoM.Scale instead for drawing a scaled image.
+//
+// If width or height is less than 1, Scale panics.
+//
+// If width or height is more than the maximum image size (see MaxImageSize), Scale returns ErrImageTooLarge.
+//
+// When the image is disposed, Scale returns an error.
+func (i *Image) Scale(width, height int, filter Filter) (*Image, error) {
+	if i.restorable == nil {
+		return nil, errors.New("ebiten: the image is already disposed")
+	}
+	dst, err := NewImage(width, height, filter)
+	if err != nil {
+		return nil, err
+	}
+	w, h := i.Size()
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(float64(width)/float64(w), float64(height)/float64(h))
+	if err := dst.DrawImage(i, op); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
 // Dispose disposes the image data. After disposing, the image becomes invalid.
 // This is useful to save memory.
 //
@@ -169,6 +492,7 @@ func (i *Image) Dispose() error {
 	}
 	i.restorable.Dispose()
 	i.restorable = nil
+	untrackImage(i)
 	runtime.SetFinalizer(i, nil)
 	return nil
 }
@@ -183,16 +507,19 @@ func (i *Image) Dispose() error {
 //
 // When the image is disposed, ReplacePixels does nothing.
 //
-// ReplacePixels always returns nil as of 1.5.0-alpha.
+// ReplacePixels returns an error when the image is a float image (see NewFloatImage).
 func (i *Image) ReplacePixels(p []uint8) error {
 	if i.restorable == nil {
 		return nil
 	}
+	if i.restorable.IsFloat() {
+		return errors.New("ebiten: ReplacePixels can't be used for float images")
+	}
 	w, h := i.restorable.Size()
 	if l := 4 * w * h; len(p) != l {
 		panic(fmt.Sprintf("ebiten: len(p) was %d but must be %d", len(p), l))
 	}
-	w2, h2 := graphics.NextPowerOf2Int(w), graphics.NextPowerOf2Int(h)
+	w2, h2 := i.restorable.TextureSize()
 	pix := make([]uint8, 4*w2*h2)
 	for j := 0; j < h; j++ {
 		copy(pix[j*w2*4:], p[j*w*4:(j+1)*w*4])
@@ -208,21 +535,207 @@ type DrawImageOptions struct {
 	ColorM        ColorM
 	CompositeMode CompositeMode
 
+	// CornerColors represents the colors at the corners of each part in the order of
+	// upper-left, upper-right, lower-left and lower-right.
+	// The colors are interpolated across the part and multiplied with the source colors after applying ColorM.
+	// A nil color is treated as white. To draw a gradient without a texture, draw a white image with CornerColors.
+	CornerColors [4]color.Color
+
+	// Transparency represents the transparency of the drawn image in the range of [0, 1].
+	// 0 is fully opaque and 1 is fully transparent, so the default value 0 doesn't change the image.
+	// Transparency is applied after ColorM with the alpha-premultiplication taken into account.
+	// Transparency is a shortcut of ColorM.Scale(1, 1, 1, 1 - transparency).
+	//
+	// For example, a fade-out can be drawn by increasing Transparency from 0 to 1.
+	//
+	// This is transparency rather than opacity so that the zero value of DrawImageOptions draws the image as it is.
+	//
+	// Transparency out of the range is clamped, e.g. when a tween overshoots.
+	Transparency float64
+
+	// PixelSnapping represents whether the image is snapped to the pixel grid.
+	//
+	// If PixelSnapping is true, the translation of the final geometry matrix
+	// (including the view matrix) is rounded to the nearest integers. This is suitable for crisp pixel art.
+	// If PixelSnapping is false, the translation is used as it is, and the image can be drawn at a subpixel position.
+	// With FilterLinear, the image is blended across pixel boundaries, which is suitable for smooth motions e.g. of UI.
+	//
+	// The default value is false.
+	PixelSnapping bool
+
+	// OriginX, OriginY, Rotation, X and Y are a declarative alternative to building GeoM by hand.
+	// They are composed into the geometry matrix in this order:
+	//
+	//   1. Translate by (-OriginX, -OriginY)
+	//   2. Rotate by Rotation
+	//   3. Translate by (X, Y)
+	//   4. Apply GeoM
+	//   5. Apply the view matrix of the destination image (see PushViewMatrix)
+	//
+	// For example, to draw an image rotated around its center at (x, y), set OriginX and OriginY to
+	// the half of the image size, Rotation to the angle and X and Y to x and y.
+	// When all of them are 0, only GeoM is used.
+	//
+	// OriginX and OriginY represent the pivot of the rotation in the source image coordinates.
+	OriginX float64
+	OriginY float64
+
+	// Rotation represents the rotation angle in radian around the pivot (OriginX, OriginY).
+	Rotation float64
+
+	// X and Y represent the position where the pivot is drawn.
+	X float64
+	Y float64
+
+	// SourceRect represents the region of the source image to draw, e.g. a frame of a sprite sheet.
+	// The region is drawn with its upper-left corner at the origin before GeoM is applied,
+	// as if it were a separate image of the size of the region.
+	//
+	// SourceRect must be in the bounds of the source image. Otherwise, DrawImage panics.
+	// If SourceRect is nil, the whole image is drawn.
+	// SourceRect is ignored when ImageParts or Parts is set.
+	SourceRect *image.Rectangle
+
 	// Deprecated (as of 1.1.0-alpha): Use ImageParts instead.
 	Parts []ImagePart
 }
 
+// geoM returns the geometry matrix composed of OriginX, OriginY, Rotation, X, Y and GeoM.
+func (o *DrawImageOptions) geoM() GeoM {
+	if o.OriginX == 0 && o.OriginY == 0 && o.Rotation == 0 && o.X == 0 && o.Y == 0 {
+		return o.GeoM
+	}
+	g := GeoM{}
+	g.Translate(-o.OriginX, -o.OriginY)
+	g.Rotate(o.Rotation)
+	g.Translate(o.X, o.Y)
+	g.Concat(o.GeoM)
+	return g
+}
+
+// npotSupported reports whether textures can be allocated with their exact sizes
+// instead of the sizes rounded up to powers of 2.
+//
+// npotSupported always returns false before the main loop starts
+// since the graphics context is not available yet.
+func npotSupported() bool {
+	c := glContext()
+	if c == nil {
+		return false
+	}
+	return c.Info().NPOT
+}
+
 // NewImage returns an empty image.
 //
-// If width or height is less than 1 or more than MaxImageSize, NewImage panics.
+// If the graphics driver supports non-power-of-2 textures (see GraphicsInfo.NPOT),
+// the underlying texture has exactly the given size.
+// Otherwise, e.g. before the main loop starts, the texture size is rounded up to a power of 2.
+//
+// If width or height is less than 1, NewImage panics.
 //
-// Error returned by NewImage is always nil as of 1.5.0-alpha.
+// If width or height is more than the maximum image size (see MaxImageSize), NewImage returns ErrImageTooLarge.
+// Otherwise, error returned by NewImage is always nil as of 1.5.0-alpha.
 func NewImage(width, height int, filter Filter) (*Image, error) {
-	checkSize(width, height)
-	r := restorable.NewImage(width, height, glFilter(filter), false)
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	min, mag := glFilters(filter, filter)
+	r := restorable.NewImage(width, height, min, mag, false, npotSupported())
+	r.Fill(color.RGBA{})
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
+	return i, nil
+}
+
+// NewImageWithFilters returns an empty image with the filters for minification and magnification.
+//
+// For example, pixel art that should stay crisp when enlarged and not shimmer when shrunk can use
+// FilterLinear for min and FilterNearest for mag.
+//
+// If width or height is less than 1, or min or mag is not a valid value, NewImageWithFilters panics.
+//
+// If width or height is more than the maximum image size (see MaxImageSize), NewImageWithFilters returns ErrImageTooLarge.
+func NewImageWithFilters(width, height int, min, mag Filter) (*Image, error) {
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	glMin, glMag := glFilters(min, mag)
+	r := restorable.NewImage(width, height, glMin, glMag, false, npotSupported())
+	r.Fill(color.RGBA{})
+	i := &Image{restorable: r, minFilter: min, magFilter: mag}
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
+	return i, nil
+}
+
+// NewExactSizeImage returns an empty image whose underlying texture has exactly the given size.
+//
+// NewImage already allocates exact sizes when possible, but falls back to
+// rounding up to a power of 2 silently.
+// NewExactSizeImage never rounds and requires the graphics driver to support
+// non-power-of-2 textures (see GraphicsInfo.NPOT).
+//
+// If width or height is less than 1, NewExactSizeImage panics.
+//
+// If width or height is more than the maximum image size (see MaxImageSize), NewExactSizeImage returns ErrImageTooLarge.
+// If non-power-of-2 textures are not supported, NewExactSizeImage returns ErrExactSizeNotSupported.
+//
+// This function can't be called before the main loop (ebiten.Run) starts.
+func NewExactSizeImage(width, height int, filter Filter) (*Image, error) {
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	c := glContext()
+	if c == nil {
+		return nil, errors.New("ebiten: NewExactSizeImage can't be called before the main loop starts")
+	}
+	if !c.Info().NPOT {
+		return nil, ErrExactSizeNotSupported
+	}
+	min, mag := glFilters(filter, filter)
+	r := restorable.NewImage(width, height, min, mag, false, true)
 	r.Fill(color.RGBA{})
-	i := &Image{r}
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
 	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
+	return i, nil
+}
+
+// NewFloatImage returns an empty image backed by a floating point (16-bit half float) texture.
+//
+// A float image is useful as an intermediate render target for HDR effects like bloom or
+// tone mapping: colors accumulated by blending (e.g. with CompositeModeLighter) are not
+// clamped to 1. Note that the output of each single draw is still in [0, 1], and
+// reading pixels back (e.g. by At or SubPixels) clamps the values to 8 bits.
+//
+// A float image is 'volatile': it is cleared at the start of each frame and its pixels
+// are not restored on GL context lost. ReplacePixels can't be used for float images.
+//
+// If width or height is less than 1, NewFloatImage panics.
+//
+// If width or height is more than the maximum image size (see MaxImageSize), NewFloatImage returns ErrImageTooLarge.
+// If floating point textures are not supported, NewFloatImage returns ErrFloatTextureNotSupported.
+//
+// This function can't be called before the main loop (ebiten.Run) starts.
+func NewFloatImage(width, height int, filter Filter) (*Image, error) {
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	c := glContext()
+	if c == nil {
+		return nil, errors.New("ebiten: NewFloatImage can't be called before the main loop starts")
+	}
+	if !c.Info().FloatTexture {
+		return nil, ErrFloatTextureNotSupported
+	}
+	min, mag := glFilters(filter, filter)
+	r := restorable.NewFloatImage(width, height, min, mag)
+	r.Fill(color.RGBA{})
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
 	return i, nil
 }
 
@@ -236,55 +749,98 @@ func NewImage(width, height int, filter Filter) (*Image, error) {
 // On the other hand, pixels in volatile images are not saved.
 // Saving pixels is an expensive operation, and it is desirable to avoid it if possible.
 //
-// If width or height is less than 1 or more than MaxImageSize, newVolatileImage panics.
+// If width or height is less than 1, newVolatileImage panics.
 //
-// Error returned by newVolatileImage is always nil as of 1.5.0-alpha.
+// If width or height is more than the maximum image size (see MaxImageSize), newVolatileImage returns ErrImageTooLarge.
 func newVolatileImage(width, height int, filter Filter) (*Image, error) {
-	checkSize(width, height)
-	r := restorable.NewImage(width, height, glFilter(filter), true)
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	min, mag := glFilters(filter, filter)
+	r := restorable.NewImage(width, height, min, mag, true, false)
 	r.Fill(color.RGBA{})
-	i := &Image{r}
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
 	runtime.SetFinalizer(i, (*Image).Dispose)
 	return i, nil
 }
 
 // NewImageFromImage creates a new image with the given image (source).
 //
-// If source's width or height is less than 1 or more than MaxImageSize, NewImageFromImage panics.
+// If source's width or height is less than 1, NewImageFromImage panics.
 //
-// Error returned by NewImageFromImage is always nil as of 1.5.0-alpha.
+// If source's width or height is more than the maximum image size (see MaxImageSize), NewImageFromImage returns ErrImageTooLarge.
+// Otherwise, error returned by NewImageFromImage is always nil as of 1.5.0-alpha.
 func NewImageFromImage(source image.Image, filter Filter) (*Image, error) {
 	size := source.Bounds().Size()
 	w, h := size.X, size.Y
-	checkSize(w, h)
-	rgbaImg := graphics.CopyImage(source)
-	r := restorable.NewImageFromImage(rgbaImg, w, h, glFilter(filter))
-	i := &Image{r}
+	if err := checkSize(w, h); err != nil {
+		return nil, err
+	}
+	exact := npotSupported()
+	rgbaImg := graphics.CopyImage(source, exact)
+	min, mag := glFilters(filter, filter)
+	r := restorable.NewImageFromImage(rgbaImg, w, h, min, mag, exact)
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
 	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
 	return i, nil
 }
 
 func newImageWithScreenFramebuffer(width, height int) (*Image, error) {
-	checkSize(width, height)
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
 	r := restorable.NewScreenFramebufferImage(width, height)
-	i := &Image{r}
+	i := &Image{restorable: r}
 	runtime.SetFinalizer(i, (*Image).Dispose)
 	return i, nil
 }
 
+// MaxImageSize represents the upper limit of the width/height of an image.
+//
+// The actual limit can be smaller depending on the graphics driver's GL_MAX_TEXTURE_SIZE,
+// which is queried when the graphics context is created (see GraphicsInfo.MaxTextureSize).
+// Before the main loop starts, only MaxImageSize is checked.
 const MaxImageSize = graphics.MaxImageSize
 
-func checkSize(width, height int) {
+// ErrImageTooLarge is returned when the requested image size is more than the maximum image size
+// (see MaxImageSize).
+//
+// Callers can compare the returned error with ErrImageTooLarge and fall back e.g. to a smaller image.
+var ErrImageTooLarge = errors.New("ebiten: image width or height is more than the maximum image size")
+
+// ErrExactSizeNotSupported is returned by NewExactSizeImage when the graphics driver doesn't support
+// non-power-of-2 textures.
+//
+// Callers can compare the returned error with ErrExactSizeNotSupported and fall back to NewImage.
+var ErrExactSizeNotSupported = errors.New("ebiten: non-power-of-2 textures are not supported")
+
+// ErrFloatTextureNotSupported is returned by NewFloatImage when the graphics driver doesn't support
+// floating point render targets.
+var ErrFloatTextureNotSupported = errors.New("ebiten: floating point textures are not supported")
+
+func checkSize(width, height int) error {
 	if width <= 0 {
 		panic("ebiten: width must be more than 0")
 	}
 	if height <= 0 {
 		panic("ebiten: height must be more than 0")
 	}
-	if width > MaxImageSize {
-		panic(fmt.Sprintf("ebiten: width must be less than or equal to %d", MaxImageSize))
+	max := maxImageSize()
+	if width > max || height > max {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// maxImageSize returns the maximum width/height of an image.
+func maxImageSize() int {
+	c := glContext()
+	if c == nil {
+		return MaxImageSize
 	}
-	if height > MaxImageSize {
-		panic(fmt.Sprintf("ebiten: height must be less than or equal to %d", MaxImageSize))
+	if s := c.Info().MaxTextureSize; 0 < s && s < MaxImageSize {
+		return s
 	}
+	return MaxImageSize
 }
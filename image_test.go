@@ -576,3 +576,939 @@ func TestImageEdge(t *testing.T) {
 		}
 	}
 }
+
+func TestImageViewMatrix(t *testing.T) {
+	src, err := NewImage(1, 1, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.RGBA{0xff, 0, 0, 0xff}); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst.PushViewMatrix(TranslateGeo(4, 2))
+	dst.PushViewMatrix(ScaleGeo(2, 2))
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(1, 1)
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst.PopViewMatrix()
+	dst.PopViewMatrix()
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			// (1, 1) - (2, 2) is scaled by 2 and then translated by (4, 2).
+			if 6 <= i && i < 8 && 4 <= j && j < 6 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageClipRect(t *testing.T) {
+	src, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.RGBA{0xff, 0, 0, 0xff}); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst.SetClipRect(image.Rect(4, 2, 8, 10))
+	if err := dst.DrawImage(src, nil); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst.ResetClipRect()
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{}
+			if 4 <= i && i < 8 && 2 <= j && j < 10 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+const benchmarkSpriteNum = 10000
+
+func benchmarkDrawImage(b *testing.B, reuse bool) {
+	src, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		b.Fatal(err)
+		return
+	}
+	dst, err := NewImage(320, 240, FilterNearest)
+	if err != nil {
+		b.Fatal(err)
+		return
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	op := &DrawImageOptions{}
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < benchmarkSpriteNum; i++ {
+			if reuse {
+				op.GeoM.Reset()
+			} else {
+				op = &DrawImageOptions{}
+			}
+			op.GeoM.Translate(float64(i%320), float64(i%240))
+			if err := dst.DrawImage(src, op); err != nil {
+				b.Fatal(err)
+				return
+			}
+		}
+		// Flush the enqueued commands.
+		_ = dst.At(0, 0)
+	}
+}
+
+func BenchmarkDrawImageNewOptions(b *testing.B) {
+	benchmarkDrawImage(b, false)
+}
+
+func BenchmarkDrawImageReusedOptions(b *testing.B) {
+	benchmarkDrawImage(b, true)
+}
+
+func TestImageTooLarge(t *testing.T) {
+	if _, err := NewImage(MaxImageSize+1, 1, FilterNearest); err != ErrImageTooLarge {
+		t.Errorf("NewImage: got %v, want: %v", err, ErrImageTooLarge)
+	}
+	src := image.NewRGBA(image.Rect(0, 0, 1, MaxImageSize+1))
+	if _, err := NewImageFromImage(src, FilterNearest); err != ErrImageTooLarge {
+		t.Errorf("NewImageFromImage: got %v, want: %v", err, ErrImageTooLarge)
+	}
+}
+
+func TestImageCornerColors(t *testing.T) {
+	src, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.White); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op := &DrawImageOptions{}
+	for i := range op.CornerColors {
+		op.CornerColors[i] = color.RGBA{0xff, 0, 0, 0xff}
+	}
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageTransparency(t *testing.T) {
+	src, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.White); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op := &DrawImageOptions{}
+	op.Transparency = 0.5
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			// The result is alpha-premultiplied.
+			if got.R != got.A || got.G != got.A || got.B != got.A || got.A < 0x7f || 0x80 < got.A {
+				t.Errorf("dst.At(%d, %d): got %v, want: about %v", i, j, got, color.RGBA{0x80, 0x80, 0x80, 0x80})
+			}
+		}
+	}
+
+	// The zero value is fully opaque.
+	dst.Clear()
+	op.Transparency = 0
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0) with Transparency 0: got %v, want: %v", got, want)
+	}
+
+	// Transparency 1 draws nothing.
+	dst.Clear()
+	op.Transparency = 1
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{}); got != want {
+		t.Errorf("dst.At(0, 0) with Transparency 1: got %v, want: %v", got, want)
+	}
+
+	// Transparency out of the range is clamped.
+	cases := []struct {
+		Transparency float64
+		Want         color.RGBA
+	}{
+		{-0.5, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{1.5, color.RGBA{}},
+		{math.NaN(), color.RGBA{0xff, 0xff, 0xff, 0xff}},
+	}
+	for _, c := range cases {
+		dst.Clear()
+		op.Transparency = c.Transparency
+		if err := dst.DrawImage(src, op); err != nil {
+			t.Fatal(err)
+			return
+		}
+		if got := dst.At(0, 0); got != c.Want {
+			t.Errorf("dst.At(0, 0) with Transparency %v: got %v, want: %v", c.Transparency, got, c.Want)
+		}
+	}
+}
+
+func TestImageToImage(t *testing.T) {
+	img0, _, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	img1, err := img0.ToImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := img1.Bounds(), img0.Bounds(); got != want {
+		t.Errorf("img1.Bounds(): got %v, want: %v", got, want)
+	}
+	w, h := img0.Size()
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img1.At(i, j)
+			want := img0.At(i, j)
+			if got != want {
+				t.Errorf("img1.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestNewExactSizeImage(t *testing.T) {
+	const w, h = 100, 60
+	dst, err := NewExactSizeImage(w, h, FilterNearest)
+	if err == ErrExactSizeNotSupported {
+		t.Skip("non-power-of-2 textures are not supported")
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	src, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	pix := make([]uint8, 4*w*h)
+	for i := range pix {
+		pix[i] = uint8(i)
+	}
+	if err := src.ReplacePixels(pix); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := dst.DrawImage(src, nil); err != nil {
+		t.Fatal(err)
+		return
+	}
+	img, err := dst.ToImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, w, h); got != want {
+		t.Errorf("img.Bounds(): got %v, want: %v", got, want)
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			want := src.At(i, j)
+			if got != want {
+				t.Errorf("img.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageSubPixels(t *testing.T) {
+	img, _, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	w, h := img.Size()
+	r := image.Rect(w/2, h/2, w+10, h+10)
+	pix, err := img.SubPixels(r)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	r = r.Intersect(img.Bounds())
+	if got, want := len(pix), 4*r.Dx()*r.Dy(); got != want {
+		t.Fatalf("len(pix): got %d, want: %d", got, want)
+	}
+	for j := 0; j < r.Dy(); j++ {
+		for i := 0; i < r.Dx(); i++ {
+			idx := 4 * (i + j*r.Dx())
+			got := color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+			want := img.At(r.Min.X+i, r.Min.Y+j)
+			if got != want {
+				t.Errorf("pixel at (%d, %d): got %v, want: %v", r.Min.X+i, r.Min.Y+j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageSubPixelsOutOfBounds(t *testing.T) {
+	img, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	cases := []struct {
+		Rect image.Rectangle
+		Len  int
+	}{
+		{image.Rect(8, 8, 17, 16), 4 * 8 * 8},
+		{image.Rect(-1, 0, 4, 4), 4 * 4 * 4},
+		{image.Rect(16, 16, 20, 20), 0},
+		{image.Rect(4, 4, 4, 8), 0},
+	}
+	for _, c := range cases {
+		pix, err := img.SubPixels(c.Rect)
+		if err != nil {
+			t.Errorf("img.SubPixels(%v): %v", c.Rect, err)
+			continue
+		}
+		if got := len(pix); got != c.Len {
+			t.Errorf("len(img.SubPixels(%v)): got %d, want: %d", c.Rect, got, c.Len)
+		}
+	}
+}
+
+func TestImagePixelsRect(t *testing.T) {
+	img, _, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	w, h := img.Size()
+	r := image.Rect(w/4, h/4, w/2, h/2)
+	pix, err := img.PixelsRect(r)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := len(pix), 4*r.Dx()*r.Dy(); got != want {
+		t.Fatalf("len(pix): got %d, want: %d", got, want)
+	}
+	for j := 0; j < r.Dy(); j++ {
+		for i := 0; i < r.Dx(); i++ {
+			idx := 4 * (i + j*r.Dx())
+			got := color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+			want := img.At(r.Min.X+i, r.Min.Y+j)
+			if got != want {
+				t.Errorf("pixel at (%d, %d): got %v, want: %v", r.Min.X+i, r.Min.Y+j, got, want)
+			}
+		}
+	}
+
+	rects := []image.Rectangle{
+		image.Rect(w/2, h/2, w+1, h),
+		image.Rect(-1, 0, 4, 4),
+		image.Rect(w, h, w+4, h+4),
+	}
+	for _, r := range rects {
+		if _, err := img.PixelsRect(r); err == nil {
+			t.Errorf("img.PixelsRect(%v): got nil error, want: non-nil", r)
+		}
+	}
+	pix, err = img.PixelsRect(image.Rect(4, 4, 4, 8))
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got := len(pix); got != 0 {
+		t.Errorf("len(pix) for an empty region: got %d, want: 0", got)
+	}
+}
+
+func TestImageDrawAt(t *testing.T) {
+	src, err := NewImage(2, 2, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.White); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	positions := []image.Point{{0, 0}, {4, 4}, {10, 2}}
+	if err := dst.DrawAt(src, positions, nil); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			want := color.RGBA{}
+			for _, p := range positions {
+				if image.Pt(i, j).In(image.Rect(p.X, p.Y, p.X+2, p.Y+2)) {
+					want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+				}
+			}
+			got := dst.At(i, j)
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawSourceRect(t *testing.T) {
+	src, err := NewImage(4, 1, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	pix := []uint8{
+		0xff, 0, 0, 0xff, 0xff, 0, 0, 0xff,
+		0, 0xff, 0, 0xff, 0, 0xff, 0, 0xff,
+	}
+	if err := src.ReplacePixels(pix); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op := &DrawImageOptions{}
+	r := image.Rect(2, 0, 4, 1)
+	op.SourceRect = &r
+	op.GeoM.Translate(1, 2)
+	if err := dst.DrawImage(src, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			want := color.RGBA{}
+			if j == 2 && (i == 1 || i == 2) {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			got := dst.At(i, j)
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDrawAt(b *testing.B) {
+	src, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		b.Fatal(err)
+		return
+	}
+	dst, err := NewImage(320, 240, FilterNearest)
+	if err != nil {
+		b.Fatal(err)
+		return
+	}
+	const n = 50000
+	positions := make([]image.Point, n)
+	for i := range positions {
+		positions[i] = image.Pt(i%320, i%240)
+	}
+	op := &DrawImageOptions{
+		CompositeMode: CompositeModeLighter,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := dst.DrawAt(src, positions, op); err != nil {
+			b.Fatal(err)
+			return
+		}
+		// Flush the enqueued commands.
+		_ = dst.At(0, 0)
+	}
+}
+
+func TestImageSubpixelSnapping(t *testing.T) {
+	src, err := NewImage(2, 2, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := src.Fill(color.White); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(8, 8, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	cases := []struct {
+		tx    float64
+		wantX int
+	}{
+		{1.49, 1},
+		{1.5, 2},
+		{2.51, 3},
+	}
+	for _, c := range cases {
+		if err := dst.Clear(); err != nil {
+			t.Fatal(err)
+			return
+		}
+		op := &DrawImageOptions{}
+		op.GeoM.Translate(c.tx, 0)
+		op.PixelSnapping = true
+		if err := dst.DrawImage(src, op); err != nil {
+			t.Fatal(err)
+			return
+		}
+		for i := 0; i < 8; i++ {
+			want := color.RGBA{}
+			if c.wantX <= i && i < c.wantX+2 {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			got := dst.At(i, 0)
+			if got != want {
+				t.Errorf("tx: %f, dst.At(%d, 0): got %v, want: %v", c.tx, i, got, want)
+			}
+		}
+	}
+}
+
+func TestImageScaledCopy(t *testing.T) {
+	src, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	pix := make([]uint8, 4*4*4)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			if (i+j)%2 == 0 {
+				idx := 4 * (i + 4*j)
+				pix[idx], pix[idx+1], pix[idx+2], pix[idx+3] = 0xff, 0xff, 0xff, 0xff
+			}
+		}
+	}
+	if err := src.ReplacePixels(pix); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := src.Scale(8, 2, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	w, h := dst.Size()
+	if w != 8 || h != 2 {
+		t.Fatalf("dst.Size(): got (%d, %d), want: (8, 2)", w, h)
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			want := src.At(i/2, j*2)
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageFilter(t *testing.T) {
+	for _, f := range []Filter{FilterNearest, FilterLinear} {
+		img, err := NewImage(16, 16, f)
+		if err != nil {
+			t.Fatal(err)
+			return
+		}
+		if got := img.Filter(); got != f {
+			t.Errorf("img.Filter(): got %v, want: %v", got, f)
+		}
+	}
+}
+
+func TestNewImageWithFilters(t *testing.T) {
+	filters := []Filter{FilterNearest, FilterLinear}
+	for _, min := range filters {
+		for _, mag := range filters {
+			img, err := NewImageWithFilters(16, 16, min, mag)
+			if err != nil {
+				t.Fatal(err)
+				return
+			}
+			gotMin, gotMag := img.Filters()
+			if gotMin != min || gotMag != mag {
+				t.Errorf("img.Filters(): got (%v, %v), want: (%v, %v)", gotMin, gotMag, min, mag)
+			}
+		}
+	}
+}
+
+func TestNewFloatImage(t *testing.T) {
+	const w, h = 16, 16
+	float, err := NewFloatImage(w, h, FilterNearest)
+	if err == ErrFloatTextureNotSupported {
+		t.Skip("floating point textures are not supported")
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	src, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	src.Fill(color.RGBA{0x40, 0x40, 0x40, 0x40})
+	// Accumulate 1.25 for each component, which exceeds 1.
+	op := &DrawImageOptions{}
+	op.CompositeMode = CompositeModeLighter
+	for i := 0; i < 5; i++ {
+		if err := float.DrawImage(src, op); err != nil {
+			t.Fatal(err)
+			return
+		}
+	}
+	if err := float.ReplacePixels(make([]uint8, 4*w*h)); err == nil {
+		t.Errorf("float.ReplacePixels must return an error")
+	}
+	dst, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op = &DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, 0.5)
+	if err := dst.DrawImage(float, op); err != nil {
+		t.Fatal(err)
+		return
+	}
+	// The alpha value 1.25 is kept in the float image, so the result is 0.625, not 0.5.
+	got := dst.At(0, 0).(color.RGBA)
+	if got.A < 0x98 || 0xa8 < got.A {
+		t.Errorf("dst.At(0, 0): got %v, want: about %v", got, color.RGBA{0xa0, 0xa0, 0xa0, 0xa0})
+	}
+}
+
+func TestImageDrawImageRotation(t *testing.T) {
+	src, _, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	w, h := src.Size()
+	dst0, err := NewImage(w*2, h*2, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst1, err := NewImage(w*2, h*2, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+	op.GeoM.Rotate(math.Pi / 2)
+	op.GeoM.Translate(float64(w), float64(h))
+	op.GeoM.Scale(0.5, 0.5)
+	dst0.DrawImage(src, op)
+
+	op = &DrawImageOptions{}
+	op.OriginX = float64(w) / 2
+	op.OriginY = float64(h) / 2
+	op.Rotation = math.Pi / 2
+	op.X = float64(w)
+	op.Y = float64(h)
+	op.GeoM.Scale(0.5, 0.5)
+	dst1.DrawImage(src, op)
+
+	for j := 0; j < h*2; j++ {
+		for i := 0; i < w*2; i++ {
+			got := dst1.At(i, j)
+			want := dst0.At(i, j)
+			if got != want {
+				t.Fatalf("dst1.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageContains(t *testing.T) {
+	src, err := NewImage(16, 8, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(2, 2)
+	op.GeoM.Translate(10, 20)
+	cases := []struct {
+		X, Y int
+		Want bool
+	}{
+		{9, 20, false},
+		{10, 20, true},
+		{41, 35, true},
+		{42, 35, false},
+		{41, 36, false},
+	}
+	for _, c := range cases {
+		got := ImageContains(op, src, c.X, c.Y)
+		if got != c.Want {
+			t.Errorf("ImageContains(op, src, %d, %d): got: %v, want: %v", c.X, c.Y, got, c.Want)
+		}
+	}
+
+	op = &DrawImageOptions{}
+	op.GeoM.Scale(0, 1)
+	if ImageContains(op, src, 0, 0) {
+		t.Errorf("ImageContains must return false for a non-invertible matrix")
+	}
+}
+
+func TestImageContainsSourceRect(t *testing.T) {
+	// The left half of the source image is transparent, and the right half is opaque.
+	pix := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	draw.Draw(pix, image.Rect(8, 0, 16, 8), image.NewUniform(color.White), image.ZP, draw.Src)
+	src, err := NewImageFromImage(pix, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	op := &DrawImageOptions{}
+	r := image.Rect(8, 0, 16, 8)
+	op.SourceRect = &r
+	op.GeoM.Translate(10, 20)
+	cases := []struct {
+		X, Y   int
+		Want   bool
+		Opaque bool
+	}{
+		{9, 20, false, false},
+		{10, 20, true, true},
+		{17, 27, true, true},
+		{18, 20, false, false},
+		{10, 28, false, false},
+	}
+	for _, c := range cases {
+		if got := ImageContains(op, src, c.X, c.Y); got != c.Want {
+			t.Errorf("ImageContains(op, src, %d, %d): got: %v, want: %v", c.X, c.Y, got, c.Want)
+		}
+		if got := ImageContainsOpaque(op, src, c.X, c.Y); got != c.Opaque {
+			t.Errorf("ImageContainsOpaque(op, src, %d, %d): got: %v, want: %v", c.X, c.Y, got, c.Opaque)
+		}
+	}
+}
+
+func TestImageUploader(t *testing.T) {
+	_, src, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	u, err := NewImageUploader(src, FilterNearest, 4)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	h := src.Bounds().Dy()
+	for i := 0; i < (h+3)/4; i++ {
+		if u.Image() != nil {
+			t.Fatalf("u.Image() must be nil before the upload is completed")
+		}
+		if err := u.Upload(); err != nil {
+			t.Fatal(err)
+			return
+		}
+	}
+	if !u.IsCompleted() {
+		t.Fatalf("u.IsCompleted(): got false, want true")
+	}
+	if got := u.Progress(); got != 1 {
+		t.Errorf("u.Progress(): got %f, want 1", got)
+	}
+	img := u.Image()
+	w := src.Bounds().Dx()
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			want := color.RGBAModel.Convert(src.At(i, j))
+			if got != want {
+				t.Fatalf("img.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageFillTranslucent(t *testing.T) {
+	const w, h = 16, 16
+	dst, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	dimmer, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	for _, clr := range []color.Color{
+		color.RGBA{0, 0, 0, 0x80},
+		color.NRGBA{0, 0, 0, 0x80},
+	} {
+		dst.Fill(color.White)
+		dimmer.Fill(clr)
+		dst.DrawImage(dimmer, nil)
+		got := dst.At(0, 0).(color.RGBA)
+		want := color.RGBA{0x7f, 0x7f, 0x7f, 0xff}
+		if 1 < diff(got.R, want.R) || 1 < diff(got.G, want.G) || 1 < diff(got.B, want.B) || 1 < diff(got.A, want.A) {
+			t.Errorf("fill with %v: got: %v, want: %v", clr, got, want)
+		}
+	}
+
+	// A color whose components exceed its alpha is clamped.
+	dimmer.Fill(color.RGBA{0xff, 0xff, 0xff, 0x80})
+	got := dimmer.At(0, 0).(color.RGBA)
+	want := color.RGBA{0x80, 0x80, 0x80, 0x80}
+	if 1 < diff(got.R, want.R) || 1 < diff(got.G, want.G) || 1 < diff(got.B, want.B) || 1 < diff(got.A, want.A) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestImageToNRGBAImage(t *testing.T) {
+	img, err := NewImage(4, 1, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	pix := []uint8{
+		0x80, 0x40, 0x00, 0x80,
+		0xff, 0xff, 0xff, 0xff,
+		0x00, 0x00, 0x00, 0x00,
+		0x10, 0x10, 0x10, 0x20,
+	}
+	if err := img.ReplacePixels(pix); err != nil {
+		t.Fatal(err)
+		return
+	}
+	nrgba, err := img.ToNRGBAImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	want := []color.NRGBA{
+		{0xff, 0x80, 0x00, 0x80},
+		{0xff, 0xff, 0xff, 0xff},
+		{0x00, 0x00, 0x00, 0x00},
+		{0x80, 0x80, 0x80, 0x20},
+	}
+	for i, w := range want {
+		if got := nrgba.NRGBAAt(i, 0); got != w {
+			t.Errorf("nrgba.NRGBAAt(%d, 0): got: %v, want: %v", i, got, w)
+		}
+	}
+}
+
+func TestImageSetFilter(t *testing.T) {
+	img, _, err := openEbitenImage("testdata/ebiten.png")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	w, h := img.Size()
+	img.SetFilter(FilterLinear)
+	if got, want := img.Filter(), FilterLinear; got != want {
+		t.Errorf("img.Filter(): got: %d, want: %d", got, want)
+	}
+	img2, err := NewImage(w, h, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := img2.DrawImage(img, nil); err != nil {
+		t.Fatal(err)
+		return
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			c0 := img.At(i, j)
+			c1 := img2.At(i, j)
+			if c0 != c1 {
+				t.Errorf("img2.At(%d, %d): got: %v, want: %v", i, j, c1, c0)
+				return
+			}
+		}
+	}
+}
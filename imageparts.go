@@ -64,3 +64,40 @@ func (w *wholeImage) Dst(i int) (x0, y0, x1, y1 int) {
 func (w *wholeImage) Src(i int) (x0, y0, x1, y1 int) {
 	return 0, 0, w.width, w.height
 }
+
+// sourceRect represents the region of the source image placed at the origin.
+type sourceRect struct {
+	rect image.Rectangle
+}
+
+func (s *sourceRect) Len() int {
+	return 1
+}
+
+func (s *sourceRect) Dst(i int) (x0, y0, x1, y1 int) {
+	return 0, 0, s.rect.Dx(), s.rect.Dy()
+}
+
+func (s *sourceRect) Src(i int) (x0, y0, x1, y1 int) {
+	return s.rect.Min.X, s.rect.Min.Y, s.rect.Max.X, s.rect.Max.Y
+}
+
+// positionedImageParts represents the whole source image placed at each position.
+type positionedImageParts struct {
+	positions []image.Point
+	width     int
+	height    int
+}
+
+func (p *positionedImageParts) Len() int {
+	return len(p.positions)
+}
+
+func (p *positionedImageParts) Dst(i int) (x0, y0, x1, y1 int) {
+	pt := p.positions[i]
+	return pt.X, pt.Y, pt.X + p.width, pt.Y + p.height
+}
+
+func (p *positionedImageParts) Src(i int) (x0, y0, x1, y1 int) {
+	return 0, 0, p.width, p.height
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/restorable"
+)
+
+// ImageUploader uploads a large image to the GPU incrementally across several frames.
+//
+// Uploading a very large image at once (e.g. by NewImageFromImage) can cause a visible hitch.
+// ImageUploader splits the upload into bands of rows, and uploads one band at each Upload call.
+// Call Upload once per frame until IsCompleted returns true, and show Progress e.g. on a loading bar.
+//
+// The image is available by Image only after the upload is completed.
+type ImageUploader struct {
+	image    *Image
+	pixels   []uint8
+	width    int
+	height   int
+	rows     int
+	uploaded int
+}
+
+// NewImageUploader returns a new ImageUploader for source.
+//
+// rowsPerUpload is the number of rows uploaded at each Upload call.
+//
+// If rowsPerUpload is less than 1, NewImageUploader panics.
+//
+// If the width or the height of source is more than the maximum image size (see MaxImageSize), NewImageUploader returns ErrImageTooLarge.
+func NewImageUploader(source image.Image, filter Filter, rowsPerUpload int) (*ImageUploader, error) {
+	if rowsPerUpload < 1 {
+		panic("ebiten: rowsPerUpload must be equal to or more than 1")
+	}
+	size := source.Bounds().Size()
+	w, h := size.X, size.Y
+	if err := checkSize(w, h); err != nil {
+		return nil, err
+	}
+	min, mag := glFilters(filter, filter)
+	exact := npotSupported()
+	r := restorable.NewImage(w, h, min, mag, false, exact)
+	r.Fill(color.RGBA{})
+	i := &Image{restorable: r, minFilter: filter, magFilter: filter}
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	trackImage(i)
+	return &ImageUploader{
+		image:  i,
+		pixels: graphics.CopyImage(source, exact).Pix,
+		width:  w,
+		height: h,
+		rows:   rowsPerUpload,
+	}, nil
+}
+
+// Upload uploads the next band of rows.
+//
+// When the upload is already completed, Upload does nothing.
+//
+// Upload always returns nil.
+func (u *ImageUploader) Upload() error {
+	if u.IsCompleted() {
+		return nil
+	}
+	if u.image.restorable == nil {
+		// The image is already disposed.
+		return nil
+	}
+	y1 := u.uploaded + u.rows
+	if y1 > u.height {
+		y1 = u.height
+	}
+	u.image.restorable.ReplacePixelsRegion(u.pixels, image.Rect(0, u.uploaded, u.width, y1))
+	u.uploaded = y1
+	if u.IsCompleted() {
+		u.pixels = nil
+	}
+	return nil
+}
+
+// Progress returns the ratio of the uploaded rows in [0, 1].
+func (u *ImageUploader) Progress() float64 {
+	return float64(u.uploaded) / float64(u.height)
+}
+
+// IsCompleted returns a boolean value indicating whether the whole image is uploaded.
+func (u *ImageUploader) IsCompleted() bool {
+	return u.uploaded == u.height
+}
+
+// Image returns the uploaded image.
+//
+// Image returns nil until the upload is completed.
+func (u *ImageUploader) Image() *Image {
+	if !u.IsCompleted() {
+		return nil
+	}
+	return u.image
+}
@@ -15,6 +15,10 @@
 package ebiten
 
 import (
+	"math"
+	"sync/atomic"
+	"time"
+
 	"github.com/hajimehoshi/ebiten/internal/ui"
 )
 
@@ -25,13 +29,61 @@ func IsKeyPressed(key Key) bool {
 	return ui.CurrentInput().IsKeyPressed(ui.Key(key))
 }
 
+// PressedKeys returns the keys that are currently pressed.
+//
+// The keys are sorted in ascending order. If no key is pressed, PressedKeys returns an empty slice.
+//
+// This function is concurrent-safe.
+func PressedKeys() []Key {
+	keys := []Key{}
+	for key := Key(0); key <= KeyMax; key++ {
+		if IsKeyPressed(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // CursorPosition returns a position of a mouse cursor.
 //
+// The position is in the logical screen coordinates regardless of the screen scale,
+// the pixel aspect ratio and the device scale.
+// When the cursor is outside of the screen, the position is out of the screen bounds
+// (e.g. negative values), so hit tests against the screen fail as expected.
+//
 // This function is concurrent-safe.
 func CursorPosition() (x, y int) {
 	return ui.CurrentInput().CursorPosition()
 }
 
+// CursorDelta returns the movement of the mouse cursor since the previous frame.
+//
+// CursorDelta is useful especially with CursorModeCaptured, where the cursor position is not meaningful.
+//
+// This function is concurrent-safe.
+func CursorDelta() (dx, dy int) {
+	return ui.CurrentInput().CursorDelta()
+}
+
+// TimeSinceLastInput returns the duration since the last input.
+//
+// Any of the following is regarded as input:
+//
+//   * A key is pressed
+//   * A mouse button is pressed
+//   * The mouse cursor is moved
+//   * A gamepad button is pressed or a gamepad axis is tilted more than half
+//   * Touches are changed
+//
+// Note that holding a key or a button is regarded as continuous input.
+// Before any input, the duration is measured from the time when the game starts.
+// TimeSinceLastInput is useful e.g. to start an attract mode after inactivity.
+//
+// This function is concurrent-safe.
+func TimeSinceLastInput() time.Duration {
+	return ui.CurrentInput().TimeSinceLastInput()
+}
+
 // IsMouseButtonPressed returns a boolean indicating whether mouseButton is pressed.
 //
 // This function is concurrent-safe.
@@ -42,6 +94,20 @@ func IsMouseButtonPressed(mouseButton MouseButton) bool {
 	return ui.CurrentInput().IsMouseButtonPressed(ui.MouseButton(mouseButton))
 }
 
+// GamepadIDs returns a slice indicating available gamepad IDs.
+//
+// This function is concurrent-safe.
+//
+// An ID is kept while the gamepad is connected, and a gamepad reconnected to the same port
+// usually gets the same ID again.
+// To detect connection or disconnection, compare the result with the one at the previous frame.
+//
+// NOTE: Gamepad API is available only on desktops, Chrome and Firefox.
+// To use this API, browsers might require rebooting the browser.
+func GamepadIDs() []int {
+	return ui.CurrentInput().GamepadIDs()
+}
+
 // GamepadAxisNum returns the number of axes of the gamepad.
 //
 // This function is concurrent-safe.
@@ -54,12 +120,58 @@ func GamepadAxisNum(id int) int {
 
 // GamepadAxis returns the float value [-1.0 - 1.0] of the axis.
 //
+// The dead zone set by SetGamepadAxisDeadZone is applied to the value.
+//
 // This function is concurrent-safe.
 //
 // NOTE: Gamepad API is available only on desktops, Chrome and Firefox.
 // To use this API, browsers might require rebooting the browser.
 func GamepadAxis(id int, axis int) float64 {
-	return ui.CurrentInput().GamepadAxis(id, axis)
+	return applyDeadZone(ui.CurrentInput().GamepadAxis(id, axis), GamepadAxisDeadZone())
+}
+
+// DefaultGamepadAxisDeadZone is the default dead zone of gamepad axes.
+const DefaultGamepadAxisDeadZone = 0.1
+
+var gamepadAxisDeadZone = math.Float64bits(DefaultGamepadAxisDeadZone)
+
+// SetGamepadAxisDeadZone sets the dead zone of gamepad axes.
+//
+// Analog sticks might not report exactly 0 at the neutral position.
+// GamepadAxis reports 0 for the values whose absolute values are equal to or less than threshold,
+// and rescales the other values so that the values change continuously from 0 to 1 (or -1).
+// 0 disables the dead zone. The default value is DefaultGamepadAxisDeadZone.
+//
+// If threshold is not in [0, 1), SetGamepadAxisDeadZone panics.
+//
+// This function is concurrent-safe.
+func SetGamepadAxisDeadZone(threshold float64) {
+	// The condition must be true when threshold is NaN.
+	if !(0 <= threshold && threshold < 1) {
+		panic("ebiten: threshold must be in [0, 1)")
+	}
+	atomic.StoreUint64(&gamepadAxisDeadZone, math.Float64bits(threshold))
+}
+
+// GamepadAxisDeadZone returns the dead zone of gamepad axes set by SetGamepadAxisDeadZone.
+//
+// This function is concurrent-safe.
+func GamepadAxisDeadZone() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&gamepadAxisDeadZone))
+}
+
+func applyDeadZone(v, threshold float64) float64 {
+	if math.Abs(v) <= threshold {
+		return 0
+	}
+	r := (math.Abs(v) - threshold) / (1 - threshold)
+	if r > 1 {
+		r = 1
+	}
+	if v < 0 {
+		return -r
+	}
+	return r
 }
 
 // GamepadButtonNum returns the number of the buttons of the gamepad.
@@ -86,6 +198,32 @@ func IsGamepadButtonPressed(id int, button GamepadButton) bool {
 	return ui.CurrentInput().IsGamepadButtonPressed(id, ui.GamepadButton(button))
 }
 
+// VibrateGamepad vibrates the gamepad specified by id for the given duration.
+//
+// strong and weak are the magnitudes of the strong (low-frequency) and the weak (high-frequency)
+// motors in [0, 1]. Values out of the range are clamped.
+//
+// VibrateGamepad does nothing when the gamepad or the environment doesn't support vibration.
+//
+// NOTE: Vibration is available only on browsers supporting the Gamepad's vibrationActuator (e.g. Chrome).
+// On desktops and mobiles, VibrateGamepad does nothing so far.
+//
+// This function is concurrent-safe.
+func VibrateGamepad(id int, strong, weak float64, duration time.Duration) {
+	ui.CurrentInput().VibrateGamepad(id, clamp01(strong), clamp01(weak), duration)
+}
+
+func clamp01(x float64) float64 {
+	// The condition must be true when x is NaN.
+	if !(0 <= x) {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
+
 // Touch represents a pointer state.
 type Touch interface {
 	ID() int
@@ -0,0 +1,53 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"testing"
+)
+
+func TestApplyDeadZone(t *testing.T) {
+	cases := []struct {
+		V         float64
+		Threshold float64
+		Want      float64
+	}{
+		{0, 0, 0},
+		{0.3, 0, 0.3},
+		{-0.3, 0, -0.3},
+
+		// Values at or inside the threshold are 0.
+		{0.25, 0.5, 0},
+		{0.5, 0.5, 0},
+		{-0.5, 0.5, 0},
+
+		// Values outside the threshold are rescaled with the sign kept.
+		{0.75, 0.5, 0.5},
+		{-0.75, 0.5, -0.5},
+		{1, 0.5, 1},
+		{-1, 0.5, -1},
+
+		// Values are clamped to [-1, 1].
+		{1.5, 0.5, 1},
+		{-1.5, 0.5, -1},
+		{2, 0, 1},
+		{-2, 0, -1},
+	}
+	for _, c := range cases {
+		if got := applyDeadZone(c.V, c.Threshold); got != c.Want {
+			t.Errorf("applyDeadZone(%v, %v): got %v, want: %v", c.V, c.Threshold, got, c.Want)
+		}
+	}
+}
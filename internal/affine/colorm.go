@@ -51,6 +51,11 @@ func (c *ColorM) UnsafeElements() []float64 {
 	return c.elements
 }
 
+// Reset resets the matrix to the identity without allocating.
+func (c *ColorM) Reset() {
+	c.elements = nil
+}
+
 // SetElement sets an element at (i, j).
 func (c *ColorM) SetElement(i, j int, element float64) {
 	if c.elements == nil {
@@ -21,105 +21,132 @@ import (
 // GeoMDim is a dimension of a GeoM.
 const GeoMDim = 3
 
-var (
-	geoMIdentityElements = []float64{
-		1, 0, 0,
-		0, 1, 0,
-	}
-)
-
 // A GeoM represents a matrix to transform geometry when rendering an image.
 //
 // The initial value is identity.
+//
+// A GeoM is a value type and none of its methods allocate memory.
 type GeoM struct {
-	// When elements is empty, this matrix is identity.
-	// elements is immutable and a new array must be created when updating.
-	elements []float64
+	// elements are the first 2 rows of the matrix.
+	// When initialized is false, this matrix is identity and elements are not used.
+	elements    [2 * GeoMDim]float64
+	initialized bool
 }
 
-func (g *GeoM) UnsafeElements() []float64 {
-	if g.elements == nil {
-		g.elements = geoMIdentityElements
+// Elements returns the first 2 rows of the matrix.
+func (g *GeoM) Elements() [2 * GeoMDim]float64 {
+	if !g.initialized {
+		return [2 * GeoMDim]float64{
+			1, 0, 0,
+			0, 1, 0,
+		}
 	}
 	return g.elements
 }
 
+// init sets the elements to identity if the matrix is not initialized yet.
+func (g *GeoM) init() {
+	if g.initialized {
+		return
+	}
+	g.elements = [2 * GeoMDim]float64{
+		1, 0, 0,
+		0, 1, 0,
+	}
+	g.initialized = true
+}
+
+// Reset resets the matrix to the identity.
+func (g *GeoM) Reset() {
+	g.initialized = false
+}
+
 // SetElement sets an element at (i, j).
 func (g *GeoM) SetElement(i, j int, element float64) {
-	if g.elements == nil {
-		g.elements = geoMIdentityElements
-	}
-	es := make([]float64, len(g.elements))
-	copy(es, g.elements)
-	es[i*GeoMDim+j] = element
-	g.elements = es
+	g.init()
+	g.elements[i*GeoMDim+j] = element
 }
 
 // Concat multiplies a geometry matrix with the other geometry matrix.
 // This is same as muptiplying the matrix other and the matrix g in this order.
 func (g *GeoM) Concat(other GeoM) {
-	if g.elements == nil {
-		g.elements = geoMIdentityElements
+	if !other.initialized {
+		return
+	}
+	if !g.initialized {
+		*g = other
+		return
 	}
-	if other.elements == nil {
-		other.elements = geoMIdentityElements
+	l, r := &other.elements, &g.elements
+	g.elements = [2 * GeoMDim]float64{
+		l[0]*r[0] + l[1]*r[3],
+		l[0]*r[1] + l[1]*r[4],
+		l[0]*r[2] + l[1]*r[5] + l[2],
+		l[3]*r[0] + l[4]*r[3],
+		l[3]*r[1] + l[4]*r[4],
+		l[3]*r[2] + l[4]*r[5] + l[5],
 	}
-	g.elements = mul(other.elements, g.elements, GeoMDim)
 }
 
 // Add is deprecated.
 func (g *GeoM) Add(other GeoM) {
-	if g.elements == nil {
-		g.elements = geoMIdentityElements
+	es := other.Elements()
+	g.init()
+	for i := range g.elements {
+		g.elements[i] += es[i]
 	}
-	if other.elements == nil {
-		other.elements = geoMIdentityElements
-	}
-	g.elements = add(other.elements, g.elements, GeoMDim)
 }
 
 // Scale scales the matrix by (x, y).
 func (g *GeoM) Scale(x, y float64) {
-	if g.elements == nil {
-		g.elements = []float64{
-			x, 0, 0,
-			0, y, 0,
-		}
-		return
-	}
-	es := make([]float64, len(g.elements))
-	copy(es, g.elements)
+	g.init()
 	for i := 0; i < GeoMDim; i++ {
-		es[i] *= x
-		es[i+GeoMDim] *= y
+		g.elements[i] *= x
+		g.elements[i+GeoMDim] *= y
 	}
-	g.elements = es
 }
 
 // Translate translates the matrix by (x, y).
 func (g *GeoM) Translate(tx, ty float64) {
-	if g.elements == nil {
-		g.elements = []float64{
-			1, 0, tx,
-			0, 1, ty,
-		}
+	g.init()
+	g.elements[2] += tx
+	g.elements[2+GeoMDim] += ty
+}
+
+// RoundTranslation rounds the translation elements to the nearest integers.
+func (g *GeoM) RoundTranslation() {
+	if !g.initialized {
 		return
 	}
-	es := make([]float64, len(g.elements))
-	copy(es, g.elements)
-	es[2] += tx
-	es[2+GeoMDim] += ty
-	g.elements = es
+	g.elements[2] = math.Floor(g.elements[2] + 0.5)
+	g.elements[2+GeoMDim] = math.Floor(g.elements[2+GeoMDim] + 0.5)
+}
+
+// InverseApply transforms the point (x, y) by the inverse matrix of g.
+//
+// ok is false when g is not invertible.
+func (g *GeoM) InverseApply(x, y float64) (ix, iy float64, ok bool) {
+	es := g.Elements()
+	a, b, tx := es[0], es[1], es[2]
+	c, d, ty := es[GeoMDim], es[GeoMDim+1], es[GeoMDim+2]
+	det := a*d - b*c
+	if det == 0 {
+		return 0, 0, false
+	}
+	x -= tx
+	y -= ty
+	return (d*x - b*y) / det, (-c*x + a*y) / det, true
 }
 
 // Rotate rotates the matrix by theta.
 func (g *GeoM) Rotate(theta float64) {
 	sin, cos := math.Sincos(theta)
 	g.Concat(GeoM{
-		elements: []float64{
+		elements: [2 * GeoMDim]float64{
 			cos, -sin, 0,
 			sin, cos, 0,
 		},
+		initialized: true,
 	})
 }
 
@@ -0,0 +1,76 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package affine_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/affine"
+)
+
+func TestGeoMConcat(t *testing.T) {
+	g := GeoM{}
+	g.Scale(2, 3)
+	other := GeoM{}
+	other.Translate(10, 20)
+	g.Concat(other)
+	want := [6]float64{
+		2, 0, 10,
+		0, 3, 20,
+	}
+	if got := g.Elements(); got != want {
+		t.Errorf("g.Elements(): got %v, want: %v", got, want)
+	}
+	// Concatenating identity doesn't change the matrix.
+	g.Concat(GeoM{})
+	if got := g.Elements(); got != want {
+		t.Errorf("g.Elements(): got %v, want: %v", got, want)
+	}
+	g.Reset()
+	identity := [6]float64{
+		1, 0, 0,
+		0, 1, 0,
+	}
+	if got, want := g.Elements(), identity; got != want {
+		t.Errorf("g.Elements() after Reset: got %v, want: %v", got, want)
+	}
+}
+
+func TestGeoMNoAllocs(t *testing.T) {
+	g := &GeoM{}
+	n := testing.AllocsPerRun(100, func() {
+		g.Reset()
+		g.Translate(1.5, 2.5)
+		g.Scale(2, 2)
+		g.Rotate(1)
+		other := GeoM{}
+		other.Translate(1, 1)
+		g.Concat(other)
+		g.RoundTranslation()
+	})
+	if n != 0 {
+		t.Errorf("allocs: got %v, want: 0", n)
+	}
+}
+
+func BenchmarkGeoMReused(b *testing.B) {
+	g := &GeoM{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.Reset()
+		g.Translate(float64(i%320), float64(i%240))
+		g.RoundTranslation()
+	}
+}
@@ -34,7 +34,11 @@ type commandQueue struct {
 	commands    []command
 	vertices    []float32
 	verticesNum int
-	m           sync.Mutex
+
+	// drawCallCount is the number of draw calls issued since the last TakeDrawCallCount call.
+	drawCallCount int
+
+	m sync.Mutex
 }
 
 var theCommandQueue = &commandQueue{
@@ -51,13 +55,17 @@ func (q *commandQueue) appendVertices(vertices []float32) {
 	q.verticesNum += len(vertices)
 }
 
-func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float32, clr affine.ColorM, mode opengl.CompositeMode) {
+func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float32, clr affine.ColorM, mode opengl.CompositeMode, clip image.Rectangle) {
 	q.m.Lock()
 	defer q.m.Unlock()
 	q.appendVertices(vertices)
+	gamma := 1.0
+	if dst.screen {
+		gamma = ScreenGamma()
+	}
 	if 0 < len(q.commands) {
 		if c, ok := q.commands[len(q.commands)-1].(*drawImageCommand); ok {
-			if c.isMergeable(dst, src, clr, mode) {
+			if c.isMergeable(dst, src, clr, mode, clip, gamma) {
 				c.verticesNum += len(vertices)
 				return
 			}
@@ -69,6 +77,8 @@ func (q *commandQueue) EnqueueDrawImageCommand(dst, src *Image, vertices []float
 		verticesNum: len(vertices),
 		color:       clr,
 		mode:        mode,
+		clip:        clip,
+		gamma:       gamma,
 	}
 	q.commands = append(q.commands, c)
 }
@@ -139,6 +149,9 @@ func (q *commandQueue) Flush(context *opengl.Context) error {
 				return err
 			}
 			if c, ok := c.(*drawImageCommand); ok {
+				if 0 < c.quadsNum() {
+					q.drawCallCount++
+				}
 				n := c.verticesNum * opengl.Float.SizeInBytes() / QuadVertexSizeInBytes()
 				indexOffsetInBytes += 6 * n * 2
 			}
@@ -158,6 +171,16 @@ func FlushCommands(context *opengl.Context) error {
 	return theCommandQueue.Flush(context)
 }
 
+// TakeDrawCallCount returns the number of draw calls issued since the last call and resets the counter.
+func TakeDrawCallCount() int {
+	q := theCommandQueue
+	q.m.Lock()
+	defer q.m.Unlock()
+	n := q.drawCallCount
+	q.drawCallCount = 0
+	return n
+}
+
 type fillCommand struct {
 	dst   *Image
 	color color.RGBA
@@ -171,6 +194,7 @@ func (c *fillCommand) Exec(context *opengl.Context, indexOffsetInBytes int) erro
 	if err := f.setAsViewport(context); err != nil {
 		return err
 	}
+	context.DisableScissor()
 	cr, cg, cb, ca := c.color.R, c.color.G, c.color.B, c.color.A
 	const max = math.MaxUint8
 	r := float64(cr) / max
@@ -186,6 +210,12 @@ type drawImageCommand struct {
 	verticesNum int
 	color       affine.ColorM
 	mode        opengl.CompositeMode
+
+	// clip is the clipping rectangle in the destination. An empty rectangle means no clipping.
+	clip image.Rectangle
+
+	// gamma is the gamma value applied to the output colors.
+	gamma float64
 }
 
 func QuadVertexSizeInBytes() int {
@@ -201,12 +231,21 @@ func (c *drawImageCommand) Exec(context *opengl.Context, indexOffsetInBytes int)
 		return err
 	}
 	context.BlendFunc(c.mode)
+	_, h := c.dst.Size()
+	if c.clip.Empty() {
+		context.DisableScissor()
+	} else {
+		y := c.clip.Min.Y
+		if f.flipY {
+			y = h - c.clip.Max.Y
+		}
+		context.SetScissor(c.clip.Min.X, y, c.clip.Dx(), c.clip.Dy())
+	}
 
 	n := c.quadsNum()
 	if n == 0 {
 		return nil
 	}
-	_, h := c.dst.Size()
 	proj := f.projectionMatrix(h)
 	p := &programContext{
 		state:            &theOpenGLState,
@@ -215,6 +254,7 @@ func (c *drawImageCommand) Exec(context *opengl.Context, indexOffsetInBytes int)
 		projectionMatrix: proj,
 		texture:          c.src.texture.native,
 		colorM:           c.color,
+		gamma:            c.gamma,
 	}
 	if err := p.begin(); err != nil {
 		return err
@@ -235,7 +275,7 @@ func (c *drawImageCommand) split(quadsNum int) [2]*drawImageCommand {
 	return [2]*drawImageCommand{&c1, &c2}
 }
 
-func (c *drawImageCommand) isMergeable(dst, src *Image, clr affine.ColorM, mode opengl.CompositeMode) bool {
+func (c *drawImageCommand) isMergeable(dst, src *Image, clr affine.ColorM, mode opengl.CompositeMode, clip image.Rectangle, gamma float64) bool {
 	if c.dst != dst {
 		return false
 	}
@@ -248,6 +288,12 @@ func (c *drawImageCommand) isMergeable(dst, src *Image, clr affine.ColorM, mode
 	if c.mode != mode {
 		return false
 	}
+	if c.clip != clip {
+		return false
+	}
+	if c.gamma != gamma {
+		return false
+	}
 	return true
 }
 
@@ -258,6 +304,9 @@ func (c *drawImageCommand) quadsNum() int {
 type replacePixelsCommand struct {
 	dst    *Image
 	pixels []uint8
+
+	// region is the region of the texture to replace. An empty rectangle means the whole texture.
+	region image.Rectangle
 }
 
 func (c *replacePixelsCommand) Exec(context *opengl.Context, indexOffsetInBytes int) error {
@@ -268,6 +317,12 @@ func (c *replacePixelsCommand) Exec(context *opengl.Context, indexOffsetInBytes
 	if err := f.setAsViewport(context); err != nil {
 		return err
 	}
+	if c.region.Empty() {
+		context.DisableScissor()
+	} else {
+		// Limit the filling below to the region so that the other pixels are kept.
+		context.SetScissor(c.region.Min.X, c.region.Min.Y, c.region.Dx(), c.region.Dy())
+	}
 	// Filling with non black or white color is required here for glTexSubImage2D.
 	// Very mysterious but this actually works (Issue #186).
 	// This is needed even after fixing a shader bug at f537378f2a6a8ef56e1acf1c03034967b77c7b51.
@@ -282,10 +337,26 @@ func (c *replacePixelsCommand) Exec(context *opengl.Context, indexOffsetInBytes
 	if err := context.BindTexture(c.dst.texture.native); err != nil {
 		return err
 	}
-	context.TexSubImage2D(c.pixels, NextPowerOf2Int(c.dst.width), NextPowerOf2Int(c.dst.height))
+	if !c.region.Empty() {
+		r := c.region
+		context.TexSubImage2D(c.pixels, r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+		return nil
+	}
+	w, h := c.dst.TextureSize()
+	context.TexSubImage2D(c.pixels, 0, 0, w, h)
 	return nil
 }
 
+type setFilterCommand struct {
+	dst       *Image
+	minFilter opengl.Filter
+	magFilter opengl.Filter
+}
+
+func (c *setFilterCommand) Exec(context *opengl.Context, indexOffsetInBytes int) error {
+	return context.SetTextureFilter(c.dst.texture.native, c.minFilter, c.magFilter)
+}
+
 type disposeCommand struct {
 	target *Image
 }
@@ -301,9 +372,10 @@ func (c *disposeCommand) Exec(context *opengl.Context, indexOffsetInBytes int) e
 }
 
 type newImageFromImageCommand struct {
-	result *Image
-	img    *image.RGBA
-	filter opengl.Filter
+	result    *Image
+	img       *image.RGBA
+	minFilter opengl.Filter
+	magFilter opengl.Filter
 }
 
 func (c *newImageFromImageCommand) Exec(context *opengl.Context, indexOffsetInBytes int) error {
@@ -315,10 +387,10 @@ func (c *newImageFromImageCommand) Exec(context *opengl.Context, indexOffsetInBy
 		return errors.New("graphics: height must be equal or more than 1.")
 	}
 	w, h := c.img.Bounds().Size().X, c.img.Bounds().Size().Y
-	if c.img.Bounds() != image.Rect(0, 0, NextPowerOf2Int(w), NextPowerOf2Int(h)) {
+	if tw, th := c.result.TextureSize(); c.img.Bounds() != image.Rect(0, 0, tw, th) {
 		panic(fmt.Sprintf("graphics: invalid image bounds: %v", c.img.Bounds()))
 	}
-	native, err := context.NewTexture(w, h, c.img.Pix, c.filter)
+	native, err := context.NewTexture(w, h, c.img.Pix, c.minFilter, c.magFilter, opengl.TextureFormatRGBA8)
 	if err != nil {
 		return err
 	}
@@ -329,22 +401,23 @@ func (c *newImageFromImageCommand) Exec(context *opengl.Context, indexOffsetInBy
 }
 
 type newImageCommand struct {
-	result *Image
-	width  int
-	height int
-	filter opengl.Filter
+	result    *Image
+	width     int
+	height    int
+	minFilter opengl.Filter
+	magFilter opengl.Filter
+	format    opengl.TextureFormat
 }
 
 func (c *newImageCommand) Exec(context *opengl.Context, indexOffsetInBytes int) error {
-	w := NextPowerOf2Int(c.width)
-	h := NextPowerOf2Int(c.height)
+	w, h := c.result.TextureSize()
 	if w < 1 {
 		return errors.New("graphics: width must be equal or more than 1.")
 	}
 	if h < 1 {
 		return errors.New("graphics: height must be equal or more than 1.")
 	}
-	native, err := context.NewTexture(w, h, nil, c.filter)
+	native, err := context.NewTexture(w, h, nil, c.minFilter, c.magFilter, c.format)
 	if err != nil {
 		return err
 	}
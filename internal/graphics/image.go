@@ -15,19 +15,29 @@
 package graphics
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
+	"math"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/opengl"
 )
 
-func CopyImage(origImg image.Image) *image.RGBA {
+// CopyImage returns an RGBA image that has the pixels of origImg at its upper-left corner.
+//
+// The returned image's size is rounded up to a power of 2 unless exactSize is true.
+func CopyImage(origImg image.Image, exactSize bool) *image.RGBA {
 	size := origImg.Bounds().Size()
 	w, h := size.X, size.Y
-	newImg := image.NewRGBA(image.Rect(0, 0, NextPowerOf2Int(w), NextPowerOf2Int(h)))
+	w2, h2 := w, h
+	if !exactSize {
+		w2, h2 = NextPowerOf2Int(w), NextPowerOf2Int(h)
+	}
+	newImg := image.NewRGBA(image.Rect(0, 0, w2, h2))
 	switch origImg := origImg.(type) {
 	case *image.Paletted:
 		b := origImg.Bounds()
@@ -74,34 +84,44 @@ type Image struct {
 	framebuffer *framebuffer
 	width       int
 	height      int
+	screen      bool
+
+	// exactSize indicates whether the texture has exactly the same size as the image.
+	// Otherwise, the texture size is rounded up to a power of 2.
+	exactSize bool
 }
 
 const MaxImageSize = viewportSize
 
-func NewImage(width, height int, filter opengl.Filter) *Image {
+func NewImage(width, height int, minFilter, magFilter opengl.Filter, exactSize bool, format opengl.TextureFormat) *Image {
 	i := &Image{
-		width:  width,
-		height: height,
+		width:     width,
+		height:    height,
+		exactSize: exactSize,
 	}
 	c := &newImageCommand{
-		result: i,
-		width:  width,
-		height: height,
-		filter: filter,
+		result:    i,
+		width:     width,
+		height:    height,
+		minFilter: minFilter,
+		magFilter: magFilter,
+		format:    format,
 	}
 	theCommandQueue.Enqueue(c)
 	return i
 }
 
-func NewImageFromImage(img *image.RGBA, width, height int, filter opengl.Filter) *Image {
+func NewImageFromImage(img *image.RGBA, width, height int, minFilter, magFilter opengl.Filter, exactSize bool) *Image {
 	i := &Image{
-		width:  width,
-		height: height,
+		width:     width,
+		height:    height,
+		exactSize: exactSize,
 	}
 	c := &newImageFromImageCommand{
-		result: i,
-		img:    img,
-		filter: filter,
+		result:    i,
+		img:       img,
+		minFilter: minFilter,
+		magFilter: magFilter,
 	}
 	theCommandQueue.Enqueue(c)
 	return i
@@ -111,6 +131,7 @@ func NewScreenFramebufferImage(width, height int) *Image {
 	i := &Image{
 		width:  width,
 		height: height,
+		screen: true,
 	}
 	c := &newScreenFramebufferImageCommand{
 		result: i,
@@ -121,6 +142,18 @@ func NewScreenFramebufferImage(width, height int) *Image {
 	return i
 }
 
+var screenGamma = math.Float64bits(1)
+
+// SetScreenGamma sets the gamma value applied when drawing to the screen framebuffer images.
+func SetScreenGamma(gamma float64) {
+	atomic.StoreUint64(&screenGamma, math.Float64bits(gamma))
+}
+
+// ScreenGamma returns the gamma value applied when drawing to the screen framebuffer images.
+func ScreenGamma() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&screenGamma))
+}
+
 func (i *Image) Dispose() {
 	c := &disposeCommand{
 		target: i,
@@ -141,8 +174,31 @@ func (i *Image) Fill(clr color.RGBA) {
 	theCommandQueue.Enqueue(c)
 }
 
-func (i *Image) DrawImage(src *Image, vertices []float32, clr affine.ColorM, mode opengl.CompositeMode) {
-	theCommandQueue.EnqueueDrawImageCommand(i, src, vertices, clr, mode)
+// SetFilter changes the filters of the texture without reallocating it.
+func (i *Image) SetFilter(minFilter, magFilter opengl.Filter) {
+	if i.screen {
+		panic("graphics: the screen framebuffer image doesn't have a texture")
+	}
+	c := &setFilterCommand{
+		dst:       i,
+		minFilter: minFilter,
+		magFilter: magFilter,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
+func (i *Image) DrawImage(src *Image, vertices []float32, clr affine.ColorM, mode opengl.CompositeMode, clip image.Rectangle) {
+	theCommandQueue.EnqueueDrawImageCommand(i, src, vertices, clr, mode, clip)
+}
+
+// TextureSize returns the size of the underlying texture.
+//
+// The texture size is rounded up to a power of 2 unless the image is created with exactSize.
+func (i *Image) TextureSize() (int, int) {
+	if i.exactSize {
+		return i.width, i.height
+	}
+	return NextPowerOf2Int(i.width), NextPowerOf2Int(i.height)
 }
 
 func (i *Image) Pixels(context *opengl.Context) ([]uint8, error) {
@@ -154,7 +210,27 @@ func (i *Image) Pixels(context *opengl.Context) ([]uint8, error) {
 	if err != nil {
 		return nil, err
 	}
-	return context.FramebufferPixels(f.native, NextPowerOf2Int(i.width), NextPowerOf2Int(i.height))
+	w, h := i.TextureSize()
+	return context.FramebufferPixels(f.native, w, h)
+}
+
+// SubPixels returns the pixels in the region r of the image.
+//
+// r must be in the bounds of the image, not the texture: the padding of a power-of-2 texture can't be read.
+// Otherwise, SubPixels returns an error.
+func (i *Image) SubPixels(context *opengl.Context, r image.Rectangle) ([]uint8, error) {
+	if r.Empty() || !r.In(image.Rect(0, 0, i.width, i.height)) {
+		return nil, fmt.Errorf("graphics: region %v is out of the image bounds (%d, %d)", r, i.width, i.height)
+	}
+	// Flush the enqueued commands so that pixels are certainly read.
+	if err := theCommandQueue.Flush(context); err != nil {
+		return nil, err
+	}
+	f, err := i.createFramebufferIfNeeded(context)
+	if err != nil {
+		return nil, err
+	}
+	return context.FramebufferSubPixels(f.native, r.Min.X, r.Min.Y, r.Dx(), r.Dy())
 }
 
 func (i *Image) ReplacePixels(p []uint8) {
@@ -167,6 +243,24 @@ func (i *Image) ReplacePixels(p []uint8) {
 	theCommandQueue.Enqueue(c)
 }
 
+// ReplacePixelsRegion replaces the pixels in the region r of the texture.
+//
+// p represents the whole pixels of the texture, and only the pixels in r are used.
+func (i *Image) ReplacePixelsRegion(p []uint8, r image.Rectangle) {
+	tw, _ := i.TextureSize()
+	pixels := make([]uint8, 4*r.Dx()*r.Dy())
+	for j := 0; j < r.Dy(); j++ {
+		idx := 4 * ((r.Min.Y+j)*tw + r.Min.X)
+		copy(pixels[4*j*r.Dx():4*(j+1)*r.Dx()], p[idx:idx+4*r.Dx()])
+	}
+	c := &replacePixelsCommand{
+		dst:    i,
+		pixels: pixels,
+		region: r,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
 func (i *Image) IsInvalidated(context *opengl.Context) bool {
 	return !context.IsTexture(i.texture.native)
 }
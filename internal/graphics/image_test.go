@@ -91,7 +91,7 @@ func TestCopyImage(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		got := CopyImage(c.In)
+		got := CopyImage(c.In, false)
 		if got.Rect != c.Out.Rect {
 			t.Errorf("Rect: %v, want: %v", got.Rect, c.Out.Rect)
 		}
@@ -109,11 +109,26 @@ func TestCopyImage(t *testing.T) {
 	}
 }
 
+func TestCopyImageExactSize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 5))
+	img.Set(2, 4, color.White)
+	if got, want := CopyImage(img, false).Rect, image.Rect(0, 0, 4, 8); got != want {
+		t.Errorf("Rect: %v, want: %v", got, want)
+	}
+	got := CopyImage(img, true)
+	if want := image.Rect(0, 0, 3, 5); got.Rect != want {
+		t.Errorf("Rect: %v, want: %v", got.Rect, want)
+	}
+	if got, want := got.At(2, 4), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("At(2, 4): %v, want: %v", got, want)
+	}
+}
+
 func BenchmarkCopyImageRGBA(b *testing.B) {
 	img := image.NewRGBA(image.Rect(0, 0, 4096, 4096))
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		CopyImage(img)
+		CopyImage(img, false)
 	}
 }
 
@@ -121,7 +136,7 @@ func BenchmarkCopyImageNRGBA(b *testing.B) {
 	img := image.NewNRGBA(image.Rect(0, 0, 4096, 4096))
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		CopyImage(img)
+		CopyImage(img, false)
 	}
 }
 
@@ -129,6 +144,6 @@ func BenchmarkCopyImagePaletted(b *testing.B) {
 	img := image.NewPaletted(image.Rect(0, 0, 4096, 4096), palette.Plan9)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		CopyImage(img)
+		CopyImage(img, false)
 	}
 }
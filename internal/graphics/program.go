@@ -97,6 +97,12 @@ var (
 				num:       2,
 				normalize: false,
 			},
+			{
+				name:      "vertex_color",
+				dataType:  opengl.Float,
+				num:       4,
+				normalize: false,
+			},
 		},
 	}
 )
@@ -110,6 +116,7 @@ type openGLState struct {
 	lastProjectionMatrix       []float32
 	lastColorMatrix            []float32
 	lastColorMatrixTranslation []float32
+	lastGamma                  float32
 }
 
 var (
@@ -137,6 +144,7 @@ func (s *openGLState) reset(context *opengl.Context) error {
 	s.lastProjectionMatrix = nil
 	s.lastColorMatrix = nil
 	s.lastColorMatrixTranslation = nil
+	s.lastGamma = 0
 
 	shaderVertexModelviewNative, err := context.NewShader(opengl.VertexShader, shader(context, shaderVertexModelview))
 	if err != nil {
@@ -193,6 +201,7 @@ type programContext struct {
 	projectionMatrix []float32
 	texture          opengl.Texture
 	colorM           affine.ColorM
+	gamma            float64
 }
 
 func (p *programContext) begin() error {
@@ -208,6 +217,7 @@ func (p *programContext) begin() error {
 		p.state.lastProjectionMatrix = nil
 		p.state.lastColorMatrix = nil
 		p.state.lastColorMatrixTranslation = nil
+		p.state.lastGamma = 0
 		c.BindElementArrayBuffer(p.state.indexBufferQuads)
 		c.UniformInt(p.program, "texture", 0)
 	}
@@ -251,6 +261,10 @@ func (p *programContext) begin() error {
 		}
 		copy(p.state.lastColorMatrixTranslation, colorMatrixTranslation)
 	}
+	if gamma := float32(1 / p.gamma); p.state.lastGamma != gamma {
+		c.UniformFloats(p.program, "gamma", []float32{gamma})
+		p.state.lastGamma = gamma
+	}
 
 	// We don't have to call gl.ActiveTexture here: GL_TEXTURE0 is the default active texture
 	// See also: https://www.opengl.org/sdk/docs/man2/xhtml/glActiveTexture.xml
@@ -43,10 +43,13 @@ attribute highp vec2 vertex;
 attribute highp vec2 tex_coord;
 attribute highp vec4 geo_matrix_body;
 attribute highp vec2 geo_matrix_translation;
+attribute lowp vec4 vertex_color;
 varying highp vec2 vertex_out_tex_coord;
+varying lowp vec4 vertex_out_color;
 
 void main(void) {
   vertex_out_tex_coord = tex_coord;
+  vertex_out_color = vertex_color;
   mat4 geo_matrix = mat4(
     vec4(geo_matrix_body[0], geo_matrix_body[2], 0, 0),
     vec4(geo_matrix_body[1], geo_matrix_body[3], 0, 0),
@@ -60,7 +63,9 @@ void main(void) {
 uniform lowp sampler2D texture;
 uniform lowp mat4 color_matrix;
 uniform lowp vec4 color_matrix_translation;
+uniform highp float gamma;
 varying highp vec2 vertex_out_tex_coord;
+varying lowp vec4 vertex_out_color;
 
 void main(void) {
   lowp vec4 color = texture2D(texture, vertex_out_tex_coord);
@@ -71,7 +76,11 @@ void main(void) {
   }
   // Apply the color matrix
   color = (color_matrix * color) + color_matrix_translation;
+  // Apply the vertex color (non-premultiplied)
+  color *= vertex_out_color;
   color = clamp(color, 0.0, 1.0);
+  // Apply the gamma correction (the exponent is the reciprocal of the gamma value)
+  color.rgb = pow(color.rgb, vec3(gamma));
   // Premultiply alpha
   color.rgb *= color.a;
 
@@ -20,6 +20,10 @@ import (
 	"time"
 )
 
+// base is the base time to calculate the monotonic time.
+// time.Since uses the monotonic clock and is not affected by changes of the wall clock.
+var base = time.Now()
+
 func now() int64 {
-	return time.Now().UnixNano()
+	return int64(time.Since(base))
 }
@@ -17,6 +17,7 @@ package loop
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/internal/opengl"
@@ -27,6 +28,37 @@ func CurrentFPS() float64 {
 	return currentRunContext.getCurrentFPS()
 }
 
+// Now returns the monotonic time since Run started.
+// Now returns 0 when Run is not called yet.
+func Now() time.Duration {
+	c := currentRunContext
+	if c == nil {
+		return 0
+	}
+	return time.Duration(now() - c.startTime)
+}
+
+func IsRunning() bool {
+	c := currentRunContext
+	if c == nil {
+		return false
+	}
+	return c.isRunning()
+}
+
+// DefaultMaxSkippedFrames is the default number of the maximum frames skipped to catch up.
+const DefaultMaxSkippedFrames = 9
+
+var maxSkippedFrames = int32(DefaultMaxSkippedFrames)
+
+func SetMaxSkippedFrames(n int) {
+	atomic.StoreInt32(&maxSkippedFrames, int32(n))
+}
+
+func MaxSkippedFrames() int {
+	return int(atomic.LoadInt32(&maxSkippedFrames))
+}
+
 type runContext struct {
 	running        bool
 	fps            int
@@ -35,6 +67,7 @@ type runContext struct {
 	frames         int64
 	lastUpdated    int64
 	lastFPSUpdated int64
+	startTime      int64
 	m              sync.RWMutex
 }
 
@@ -75,7 +108,7 @@ func (c *runContext) updateFPS(fps float64) {
 }
 
 type GraphicsContext interface {
-	SetSize(width, height int, scale float64) error
+	SetSize(width, height int, scale float64, pixelAspectRatio float64) error
 	UpdateAndDraw(context *opengl.Context, updateCount int) error
 	Invalidate()
 }
@@ -85,8 +118,8 @@ type loopGraphicsContext struct {
 	graphicsContext GraphicsContext
 }
 
-func (g *loopGraphicsContext) SetSize(width, height int, scale float64) error {
-	return g.graphicsContext.SetSize(width, height, scale)
+func (g *loopGraphicsContext) SetSize(width, height int, scale float64, pixelAspectRatio float64) error {
+	return g.graphicsContext.SetSize(width, height, scale, pixelAspectRatio)
 }
 
 func (g *loopGraphicsContext) Update() error {
@@ -102,7 +135,8 @@ func Run(g GraphicsContext, width, height int, scale float64, title string, fps
 		return errors.New("loop: The game is already running")
 	}
 	currentRunContext = &runContext{
-		fps: fps,
+		fps:       fps,
+		startTime: now(),
 	}
 	currentRunContext.startRunning()
 	defer currentRunContext.endRunning()
@@ -150,6 +184,12 @@ func (c *runContext) render(g GraphicsContext) error {
 	if tt == 0 && (int64(time.Second)/int64(fps)-int64(5*time.Millisecond)) < t {
 		tt = 1
 	}
+	// Limit the number of updates without drawing. The remaining time is dropped
+	// so that slow updates don't cause more updates to catch up.
+	if max := MaxSkippedFrames() + 1; max < tt {
+		tt = max
+		c.lastUpdated = n - int64(tt)*int64(time.Second)/int64(fps)
+	}
 	if err := g.UpdateAndDraw(ui.GLContext(), tt); err != nil {
 		return err
 	}
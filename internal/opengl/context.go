@@ -44,6 +44,9 @@ type Context struct {
 	lastViewportWidth  int
 	lastViewportHeight int
 	lastCompositeMode  CompositeMode
+	scissorEnabled     bool
+	lastScissor        [4]int
+	info               Info
 	context
 }
 
@@ -91,3 +94,23 @@ func (c *Context) ResetViewportSize() {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 }
+
+// SetScissor enables the scissor test with the given rectangle in the framebuffer coordinates.
+func (c *Context) SetScissor(x, y, width, height int) {
+	r := [4]int{x, y, width, height}
+	if c.scissorEnabled && c.lastScissor == r {
+		return
+	}
+	c.setScissorImpl(x, y, width, height)
+	c.scissorEnabled = true
+	c.lastScissor = r
+}
+
+// DisableScissor disables the scissor test.
+func (c *Context) DisableScissor() {
+	if !c.scissorEnabled {
+		return
+	}
+	c.disableScissorImpl()
+	c.scissorEnabled = false
+}
@@ -22,6 +22,7 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-gl/gl/v2.1/gl"
 )
@@ -71,6 +72,9 @@ func init() {
 type context struct {
 	init            bool
 	runOnMainThread func(func() error) error
+
+	customScreenFramebuffer    Framebuffer
+	useCustomScreenFramebuffer bool
 }
 
 func NewContext(runOnMainThread func(func() error) error) (*Context, error) {
@@ -83,6 +87,31 @@ func (c *Context) runOnContextThread(f func() error) error {
 	return c.runOnMainThread(f)
 }
 
+func desktopInfo() Info {
+	version := gl.GoStr(gl.GetString(gl.VERSION))
+	extensions := strings.Fields(gl.GoStr(gl.GetString(gl.EXTENSIONS)))
+	major, _, _ := parseGLVersion(version)
+	maxTextureSize := int32(0)
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &maxTextureSize)
+	return Info{
+		Version:  version,
+		Renderer: gl.GoStr(gl.GetString(gl.RENDERER)),
+		// NPOT textures are in the core since OpenGL 2.0.
+		NPOT: major >= 2 || hasExtension(extensions, "GL_ARB_texture_non_power_of_two"),
+		// Framebuffer objects are in the core since OpenGL 3.0.
+		FramebufferObject: major >= 3 || hasExtension(extensions, "GL_ARB_framebuffer_object", "GL_EXT_framebuffer_object"),
+		Anisotropy:        hasExtension(extensions, "GL_EXT_texture_filter_anisotropic", "GL_ARB_texture_filter_anisotropic"),
+		// Floating point textures are in the core since OpenGL 3.0.
+		FloatTexture:   major >= 3 || hasExtension(extensions, "GL_ARB_texture_float"),
+		MaxTextureSize: int(maxTextureSize),
+	}
+}
+
+// RunOnContextThread runs f on the thread where the GL context is current and waits until f finishes.
+func (c *Context) RunOnContextThread(f func() error) error {
+	return c.runOnContextThread(f)
+}
+
 func (c *Context) Reset() error {
 	if err := c.runOnContextThread(func() error {
 		if c.init {
@@ -92,10 +121,23 @@ func (c *Context) Reset() error {
 		if err := gl.Init(); err != nil {
 			return fmt.Errorf("opengl: initializing error %v", err)
 		}
+		c.info = desktopInfo()
+		// Check the version explicitly so that an insufficient environment
+		// results in a clear error rather than a cryptic failure at a later draw call.
+		major, minor, ok := parseGLVersion(c.info.Version)
+		if !ok {
+			return fmt.Errorf("opengl: invalid OpenGL version: %q", c.info.Version)
+		}
+		if major < 2 || (major == 2 && minor < 1) {
+			return fmt.Errorf("opengl: OpenGL 2.1 or later is required but the version is %s (%s)", c.info.Version, c.info.Renderer)
+		}
+		if !c.info.FramebufferObject {
+			return fmt.Errorf("opengl: framebuffer objects are required but not supported (%s)", c.info.Renderer)
+		}
 		c.init = true
 		return nil
 	}); err != nil {
-		return nil
+		return err
 	}
 	c.locationCache = newLocationCache()
 	c.lastTexture = invalidTexture
@@ -103,13 +145,19 @@ func (c *Context) Reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = CompositeModeUnknown
+	c.scissorEnabled = false
 	if err := c.runOnContextThread(func() error {
 		gl.Enable(gl.BLEND)
+		gl.Disable(gl.SCISSOR_TEST)
 		return nil
 	}); err != nil {
 		return err
 	}
 	c.BlendFunc(CompositeModeSourceOver)
+	if c.useCustomScreenFramebuffer {
+		c.screenFramebuffer = c.customScreenFramebuffer
+		return nil
+	}
 	if err := c.runOnContextThread(func() error {
 		f := int32(0)
 		gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &f)
@@ -121,6 +169,16 @@ func (c *Context) Reset() error {
 	return nil
 }
 
+// SetScreenFramebuffer specifies the framebuffer object that is used as the screen
+// instead of the one bound when Reset is called.
+//
+// The framebuffer must belong to the GL context this Context works on.
+// The new value takes effect at the next Reset.
+func (c *Context) SetScreenFramebuffer(f uint32) {
+	c.customScreenFramebuffer = Framebuffer(f)
+	c.useCustomScreenFramebuffer = true
+}
+
 func (c *Context) BlendFunc(mode CompositeMode) {
 	_ = c.runOnContextThread(func() error {
 		if c.lastCompositeMode == mode {
@@ -133,7 +191,15 @@ func (c *Context) BlendFunc(mode CompositeMode) {
 	})
 }
 
-func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (Texture, error) {
+func (c *Context) NewTexture(width, height int, pixels []uint8, minFilter, magFilter Filter, format TextureFormat) (Texture, error) {
+	if format != TextureFormatRGBA8 {
+		if !c.info.FloatTexture {
+			return 0, errors.New("opengl: floating point textures are not supported")
+		}
+		if pixels != nil {
+			return 0, errors.New("opengl: pixels must be nil for floating point textures")
+		}
+	}
 	var texture Texture
 	if err := c.runOnContextThread(func() error {
 		var t uint32
@@ -152,8 +218,8 @@ func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (
 		return 0, err
 	}
 	if err := c.runOnContextThread(func() error {
-		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(filter))
-		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(filter))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(magFilter))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(minFilter))
 		//gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP)
 		//gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP)
 
@@ -161,6 +227,12 @@ func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (
 		if pixels != nil {
 			p = pixels
 		}
+		if format == TextureFormatRGBA16F {
+			// GL_RGBA16F (GL_RGBA16F_ARB)
+			const rgba16f = 0x881A
+			gl.TexImage2D(gl.TEXTURE_2D, 0, rgba16f, int32(width), int32(height), 0, gl.RGBA, gl.FLOAT, nil)
+			return nil
+		}
 		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(p))
 		return nil
 	}); err != nil {
@@ -180,6 +252,11 @@ func (c *Context) bindFramebufferImpl(f Framebuffer) error {
 }
 
 func (c *Context) FramebufferPixels(f Framebuffer, width, height int) ([]uint8, error) {
+	return c.FramebufferSubPixels(f, 0, 0, width, height)
+}
+
+// FramebufferSubPixels reads the pixels in the rectangle (x, y, width, height) of the framebuffer.
+func (c *Context) FramebufferSubPixels(f Framebuffer, x, y, width, height int) ([]uint8, error) {
 	var pixels []uint8
 	if err := c.runOnContextThread(func() error {
 		gl.Flush()
@@ -192,7 +269,7 @@ func (c *Context) FramebufferPixels(f Framebuffer, width, height int) ([]uint8,
 	}
 	if err := c.runOnContextThread(func() error {
 		pixels = make([]uint8, 4*width*height)
-		gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+		gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
 		if e := gl.GetError(); e != gl.NO_ERROR {
 			pixels = nil
 			return fmt.Errorf("opengl: glReadPixels: %d", e)
@@ -235,9 +312,21 @@ func (c *Context) IsTexture(t Texture) bool {
 	return r
 }
 
-func (c *Context) TexSubImage2D(p []uint8, width, height int) {
+func (c *Context) TexSubImage2D(p []uint8, x, y, width, height int) {
 	_ = c.runOnContextThread(func() error {
-		gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(p))
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(p))
+		return nil
+	})
+}
+
+// SetTextureFilter changes the filters of the existing texture t.
+func (c *Context) SetTextureFilter(t Texture, minFilter, magFilter Filter) error {
+	if err := c.BindTexture(t); err != nil {
+		return err
+	}
+	return c.runOnContextThread(func() error {
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(magFilter))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(minFilter))
 		return nil
 	})
 }
@@ -289,6 +378,21 @@ func (c *Context) setViewportImpl(width, height int) error {
 	})
 }
 
+func (c *Context) setScissorImpl(x, y, width, height int) {
+	_ = c.runOnContextThread(func() error {
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Scissor(int32(x), int32(y), int32(width), int32(height))
+		return nil
+	})
+}
+
+func (c *Context) disableScissorImpl() {
+	_ = c.runOnContextThread(func() error {
+		gl.Disable(gl.SCISSOR_TEST)
+		return nil
+	})
+}
+
 func (c *Context) FillFramebuffer(r, g, b, a float64) error {
 	return c.runOnContextThread(func() error {
 		gl.ClearColor(float32(r), float32(g), float32(b), float32(a))
@@ -417,6 +521,8 @@ func (c *Context) UniformFloats(p Program, location string, v []float32) {
 	_ = c.runOnContextThread(func() error {
 		l := int32(c.locationCache.GetUniformLocation(c, p, location))
 		switch len(v) {
+		case 1:
+			gl.Uniform1f(l, v[0])
 		case 4:
 			gl.Uniform4fv(l, 1, (*float32)(gl.Ptr(v)))
 		case 16:
@@ -516,3 +622,11 @@ func (c *Context) Flush() {
 		return nil
 	})
 }
+
+// Finish blocks until all the issued commands are completed.
+func (c *Context) Finish() {
+	_ = c.runOnContextThread(func() error {
+		gl.Finish()
+		return nil
+	})
+}
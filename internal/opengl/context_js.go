@@ -88,9 +88,17 @@ func init() {
 type context struct {
 	gl            *webgl.Context
 	loseContext   *js.Object
+	halfFloat     *js.Object
 	lastProgramID programID
 }
 
+// RunOnContextThread runs f on the thread where the GL context is current and waits until f finishes.
+//
+// As browsers have only one thread, f is just called.
+func (c *Context) RunOnContextThread(f func() error) error {
+	return f()
+}
+
 func NewContext() (*Context, error) {
 	var gl *webgl.Context
 
@@ -120,6 +128,23 @@ func NewContext() (*Context, error) {
 	// Getting an extension might fail after the context is lost, so
 	// it is required to get the extension here.
 	c.loseContext = gl.GetExtension("WEBGL_lose_context")
+	extensions := gl.GetSupportedExtensions()
+	c.info = Info{
+		Version:  gl.GetParameter(gl.VERSION).String(),
+		Renderer: gl.GetParameter(gl.RENDERER).String(),
+		// WebGL 1 supports NPOT textures only without mipmaps and repeating.
+		NPOT:              false,
+		FramebufferObject: true,
+		Anisotropy:        hasExtension(extensions, "EXT_texture_filter_anisotropic", "WEBKIT_EXT_texture_filter_anisotropic", "MOZ_EXT_texture_filter_anisotropic"),
+		MaxTextureSize:    gl.GetParameter(gl.MAX_TEXTURE_SIZE).Int(),
+	}
+	// Half float textures must be enabled by getting the extension.
+	// Rendering to half float textures requires EXT_color_buffer_half_float in addition.
+	c.halfFloat = gl.GetExtension("OES_texture_half_float")
+	if c.halfFloat != nil && gl.GetExtension("EXT_color_buffer_half_float") == nil {
+		c.halfFloat = nil
+	}
+	c.info.FloatTexture = c.halfFloat != nil
 	if c.loseContext != nil {
 		// This testing function name is temporary.
 		js.Global.Set("_ebiten_loseContextForTesting", func() {
@@ -136,8 +161,10 @@ func (c *Context) Reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = CompositeModeUnknown
+	c.scissorEnabled = false
 	gl := c.gl
 	gl.Enable(gl.BLEND)
+	gl.Disable(gl.SCISSOR_TEST)
 	c.BlendFunc(CompositeModeSourceOver)
 	f := gl.GetParameter(gl.FRAMEBUFFER_BINDING)
 	c.screenFramebuffer = Framebuffer{f}
@@ -154,7 +181,15 @@ func (c *Context) BlendFunc(mode CompositeMode) {
 	gl.BlendFunc(int(s), int(d))
 }
 
-func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (Texture, error) {
+func (c *Context) NewTexture(width, height int, pixels []uint8, minFilter, magFilter Filter, format TextureFormat) (Texture, error) {
+	if format != TextureFormatRGBA8 {
+		if !c.info.FloatTexture {
+			return Texture{nil}, errors.New("opengl: floating point textures are not supported")
+		}
+		if pixels != nil {
+			return Texture{nil}, errors.New("opengl: pixels must be nil for floating point textures")
+		}
+	}
 	gl := c.gl
 	t := gl.CreateTexture()
 	if t == nil {
@@ -165,8 +200,8 @@ func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (
 		return Texture{}, err
 	}
 
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int(filter))
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int(filter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int(magFilter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int(minFilter))
 
 	// TODO: Can we use glTexSubImage2D with linear filtering?
 
@@ -177,6 +212,10 @@ func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (
 	if pixels != nil {
 		p = pixels
 	}
+	if format == TextureFormatRGBA16F {
+		gl.Call("texImage2D", gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, c.halfFloat.Get("HALF_FLOAT_OES").Int(), nil)
+		return Texture{t}, nil
+	}
 	gl.Call("texImage2D", gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, p)
 
 	return Texture{t}, nil
@@ -189,12 +228,17 @@ func (c *Context) bindFramebufferImpl(f Framebuffer) error {
 }
 
 func (c *Context) FramebufferPixels(f Framebuffer, width, height int) ([]uint8, error) {
+	return c.FramebufferSubPixels(f, 0, 0, width, height)
+}
+
+// FramebufferSubPixels reads the pixels in the rectangle (x, y, width, height) of the framebuffer.
+func (c *Context) FramebufferSubPixels(f Framebuffer, x, y, width, height int) ([]uint8, error) {
 	gl := c.gl
 
 	c.bindFramebuffer(f)
 
 	pixels := js.Global.Get("Uint8Array").New(4 * width * height)
-	gl.ReadPixels(0, 0, width, height, gl.RGBA, gl.UNSIGNED_BYTE, pixels)
+	gl.ReadPixels(x, y, width, height, gl.RGBA, gl.UNSIGNED_BYTE, pixels)
 	if e := gl.GetError(); e != gl.NO_ERROR {
 		return nil, errors.New(fmt.Sprintf("opengl: error: %d", e))
 	}
@@ -224,12 +268,23 @@ func (c *Context) IsTexture(t Texture) bool {
 	return b
 }
 
-func (c *Context) TexSubImage2D(p []uint8, width, height int) {
+func (c *Context) TexSubImage2D(p []uint8, x, y, width, height int) {
 	gl := c.gl
 	// void texSubImage2D(GLenum target, GLint level, GLint xoffset, GLint yoffset,
 	//                    GLsizei width, GLsizei height,
 	//                    GLenum format, GLenum type, ArrayBufferView? pixels);
-	gl.Call("texSubImage2D", gl.TEXTURE_2D, 0, 0, 0, width, height, gl.RGBA, gl.UNSIGNED_BYTE, p)
+	gl.Call("texSubImage2D", gl.TEXTURE_2D, 0, x, y, width, height, gl.RGBA, gl.UNSIGNED_BYTE, p)
+}
+
+// SetTextureFilter changes the filters of the existing texture t.
+func (c *Context) SetTextureFilter(t Texture, minFilter, magFilter Filter) error {
+	if err := c.BindTexture(t); err != nil {
+		return err
+	}
+	gl := c.gl
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int(magFilter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int(minFilter))
+	return nil
 }
 
 func (c *Context) NewFramebuffer(t Texture) (Framebuffer, error) {
@@ -252,6 +307,17 @@ func (c *Context) setViewportImpl(width, height int) error {
 	return nil
 }
 
+func (c *Context) setScissorImpl(x, y, width, height int) {
+	gl := c.gl
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x, y, width, height)
+}
+
+func (c *Context) disableScissorImpl() {
+	gl := c.gl
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
 func (c *Context) FillFramebuffer(r, g, b, a float64) error {
 	// TODO: Use f?
 	gl := c.gl
@@ -354,6 +420,8 @@ func (c *Context) UniformFloats(p Program, location string, v []float32) {
 	gl := c.gl
 	l := c.locationCache.GetUniformLocation(c, p, location)
 	switch len(v) {
+	case 1:
+		gl.Uniform1f(l.Object, v[0])
 	case 4:
 		gl.Call("uniform4fv", l.Object, v)
 	case 16:
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/internal/endian"
 	mgl "golang.org/x/mobile/gl"
@@ -70,14 +71,33 @@ func init() {
 type context struct {
 	gl     mgl.Context
 	worker mgl.Worker
+	funcs  chan func()
+
+	customScreenFramebuffer    Framebuffer
+	useCustomScreenFramebuffer bool
 }
 
 func NewContext() (*Context, error) {
 	c := &Context{}
 	c.gl, c.worker = mgl.NewContext()
+	c.funcs = make(chan func())
 	return c, nil
 }
 
+// RunOnContextThread runs f on the thread where the GL context is current and waits until f finishes.
+//
+// f is executed in DoWork. Then, RunOnContextThread blocks until the next rendering.
+func (c *Context) RunOnContextThread(f func() error) error {
+	ch := make(chan struct{})
+	var err error
+	c.funcs <- func() {
+		err = f()
+		close(ch)
+	}
+	<-ch
+	return err
+}
+
 func (c *Context) DoWork(chError <-chan error, chDone <-chan struct{}) error {
 	// TODO: Check this is called on the rendering thread
 loop:
@@ -87,6 +107,8 @@ loop:
 			return err
 		case <-c.worker.WorkAvailable():
 			c.worker.DoWork()
+		case f := <-c.funcs:
+			f()
 		case <-chDone:
 			break loop
 		}
@@ -101,14 +123,42 @@ func (c *Context) Reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = CompositeModeUnknown
+	c.scissorEnabled = false
+	extensions := strings.Fields(c.gl.GetString(mgl.EXTENSIONS))
+	c.info = Info{
+		Version:  c.gl.GetString(mgl.VERSION),
+		Renderer: c.gl.GetString(mgl.RENDERER),
+		// OpenGL ES 2.0 supports NPOT textures only without mipmaps and repeating.
+		NPOT:              hasExtension(extensions, "GL_OES_texture_npot", "GL_ARB_texture_non_power_of_two"),
+		FramebufferObject: true,
+		Anisotropy:        hasExtension(extensions, "GL_EXT_texture_filter_anisotropic"),
+		// Rendering to half float textures requires EXT_color_buffer_half_float in addition.
+		FloatTexture:   hasExtension(extensions, "GL_OES_texture_half_float") && hasExtension(extensions, "GL_EXT_color_buffer_half_float"),
+		MaxTextureSize: c.gl.GetInteger(mgl.MAX_TEXTURE_SIZE),
+	}
 	c.gl.Enable(mgl.BLEND)
+	c.gl.Disable(mgl.SCISSOR_TEST)
 	c.BlendFunc(CompositeModeSourceOver)
+	if c.useCustomScreenFramebuffer {
+		c.screenFramebuffer = c.customScreenFramebuffer
+		return nil
+	}
 	f := c.gl.GetInteger(mgl.FRAMEBUFFER_BINDING)
 	c.screenFramebuffer = Framebuffer(mgl.Framebuffer{uint32(f)})
 	// TODO: Need to update screenFramebufferWidth/Height?
 	return nil
 }
 
+// SetScreenFramebuffer specifies the framebuffer object that is used as the screen
+// instead of the one bound when Reset is called.
+//
+// The framebuffer must belong to the GL context this Context works on.
+// The new value takes effect at the next Reset.
+func (c *Context) SetScreenFramebuffer(f uint32) {
+	c.customScreenFramebuffer = Framebuffer(mgl.Framebuffer{f})
+	c.useCustomScreenFramebuffer = true
+}
+
 func (c *Context) BlendFunc(mode CompositeMode) {
 	gl := c.gl
 	if c.lastCompositeMode == mode {
@@ -119,7 +169,15 @@ func (c *Context) BlendFunc(mode CompositeMode) {
 	gl.BlendFunc(mgl.Enum(s), mgl.Enum(d))
 }
 
-func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (Texture, error) {
+func (c *Context) NewTexture(width, height int, pixels []uint8, minFilter, magFilter Filter, format TextureFormat) (Texture, error) {
+	if format != TextureFormatRGBA8 {
+		if !c.info.FloatTexture {
+			return Texture{}, errors.New("opengl: floating point textures are not supported")
+		}
+		if pixels != nil {
+			return Texture{}, errors.New("opengl: pixels must be nil for floating point textures")
+		}
+	}
 	gl := c.gl
 	t := gl.CreateTexture()
 	if t.Value <= 0 {
@@ -130,13 +188,19 @@ func (c *Context) NewTexture(width, height int, pixels []uint8, filter Filter) (
 		return Texture{}, err
 	}
 
-	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MAG_FILTER, int(filter))
-	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MIN_FILTER, int(filter))
+	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MAG_FILTER, int(magFilter))
+	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MIN_FILTER, int(minFilter))
 
 	var p []uint8
 	if pixels != nil {
 		p = pixels
 	}
+	if format == TextureFormatRGBA16F {
+		// GL_HALF_FLOAT_OES
+		const halfFloatOES = 0x8D61
+		gl.TexImage2D(mgl.TEXTURE_2D, 0, width, height, mgl.RGBA, halfFloatOES, nil)
+		return Texture(t), nil
+	}
 	gl.TexImage2D(mgl.TEXTURE_2D, 0, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE, p)
 
 	return Texture(t), nil
@@ -149,13 +213,18 @@ func (c *Context) bindFramebufferImpl(f Framebuffer) error {
 }
 
 func (c *Context) FramebufferPixels(f Framebuffer, width, height int) ([]uint8, error) {
+	return c.FramebufferSubPixels(f, 0, 0, width, height)
+}
+
+// FramebufferSubPixels reads the pixels in the rectangle (x, y, width, height) of the framebuffer.
+func (c *Context) FramebufferSubPixels(f Framebuffer, x, y, width, height int) ([]uint8, error) {
 	gl := c.gl
 	gl.Flush()
 
 	c.bindFramebuffer(f)
 
 	pixels := make([]uint8, 4*width*height)
-	gl.ReadPixels(pixels, 0, 0, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE)
+	gl.ReadPixels(pixels, x, y, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE)
 	if e := gl.GetError(); e != mgl.NO_ERROR {
 		return nil, fmt.Errorf("opengl: glReadPixels: %d", e)
 	}
@@ -184,9 +253,20 @@ func (c *Context) IsTexture(t Texture) bool {
 	return gl.IsTexture(mgl.Texture(t))
 }
 
-func (c *Context) TexSubImage2D(p []uint8, width, height int) {
+func (c *Context) TexSubImage2D(p []uint8, x, y, width, height int) {
 	gl := c.gl
-	gl.TexSubImage2D(mgl.TEXTURE_2D, 0, 0, 0, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE, p)
+	gl.TexSubImage2D(mgl.TEXTURE_2D, 0, x, y, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE, p)
+}
+
+// SetTextureFilter changes the filters of the existing texture t.
+func (c *Context) SetTextureFilter(t Texture, minFilter, magFilter Filter) error {
+	if err := c.BindTexture(t); err != nil {
+		return err
+	}
+	gl := c.gl
+	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MAG_FILTER, int(magFilter))
+	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_MIN_FILTER, int(minFilter))
+	return nil
 }
 
 func (c *Context) NewFramebuffer(texture Texture) (Framebuffer, error) {
@@ -217,6 +297,17 @@ func (c *Context) setViewportImpl(width, height int) error {
 	return nil
 }
 
+func (c *Context) setScissorImpl(x, y, width, height int) {
+	gl := c.gl
+	gl.Enable(mgl.SCISSOR_TEST)
+	gl.Scissor(int32(x), int32(y), int32(width), int32(height))
+}
+
+func (c *Context) disableScissorImpl() {
+	gl := c.gl
+	gl.Disable(mgl.SCISSOR_TEST)
+}
+
 func (c *Context) FillFramebuffer(r, g, b, a float64) error {
 	gl := c.gl
 	gl.ClearColor(float32(r), float32(g), float32(b), float32(a))
@@ -322,6 +413,8 @@ func (c *Context) UniformFloats(p Program, location string, v []float32) {
 	gl := c.gl
 	l := mgl.Uniform(c.locationCache.GetUniformLocation(c, p, location))
 	switch len(v) {
+	case 1:
+		gl.Uniform1f(l, v[0])
 	case 4:
 		gl.Uniform4fv(l, v)
 	case 16:
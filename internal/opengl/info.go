@@ -0,0 +1,83 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"strings"
+)
+
+// Info represents the information of the graphics driver.
+type Info struct {
+	// Version is the version string of OpenGL, OpenGL ES or WebGL.
+	Version string
+
+	// Renderer is the name of the renderer (typically the GPU).
+	Renderer string
+
+	// NPOT reports whether textures whose sizes are not powers of 2 are fully supported.
+	NPOT bool
+
+	// FramebufferObject reports whether framebuffer objects are supported.
+	FramebufferObject bool
+
+	// Anisotropy reports whether anisotropic texture filtering is supported.
+	Anisotropy bool
+
+	// FloatTexture reports whether floating point textures (TextureFormatRGBA16F) can be used as render targets.
+	FloatTexture bool
+
+	// MaxTextureSize is the maximum width/height of a texture (GL_MAX_TEXTURE_SIZE).
+	MaxTextureSize int
+}
+
+// Info returns the information of the graphics driver.
+//
+// Info is available after Reset is called.
+func (c *Context) Info() Info {
+	return c.info
+}
+
+func hasExtension(extensions []string, names ...string) bool {
+	for _, e := range extensions {
+		for _, n := range names {
+			if e == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseGLVersion parses the major and the minor versions at the head of str like "2.1 Mesa 10.1.3".
+func parseGLVersion(str string) (major, minor int, ok bool) {
+	// The version string of OpenGL ES starts with "OpenGL ES ".
+	str = strings.TrimPrefix(str, "OpenGL ES ")
+	i := 0
+	for ; i < len(str) && '0' <= str[i] && str[i] <= '9'; i++ {
+		major = major*10 + int(str[i]-'0')
+	}
+	if i == 0 || i >= len(str) || str[i] != '.' {
+		return 0, 0, false
+	}
+	i++
+	j := i
+	for ; i < len(str) && '0' <= str[i] && str[i] <= '9'; i++ {
+		minor = minor*10 + int(str[i]-'0')
+	}
+	if i == j {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -23,6 +23,18 @@ type operation int
 
 type CompositeMode int
 
+// TextureFormat represents the format of a texture.
+type TextureFormat int
+
+const (
+	// TextureFormatRGBA8 represents 8bit unsigned normalized RGBA.
+	TextureFormatRGBA8 TextureFormat = iota // This value must be 0 (= initial value)
+
+	// TextureFormatRGBA16F represents 16bit floating point RGBA.
+	// This is available only when Info.FloatTexture is true.
+	TextureFormatRGBA16F
+)
+
 const (
 	CompositeModeSourceOver CompositeMode = iota // This value must be 0 (= initial value)
 	CompositeModeClear
@@ -32,12 +32,14 @@ type drawImageHistoryItem struct {
 	vertices []float32
 	colorm   affine.ColorM
 	mode     opengl.CompositeMode
+	clip     image.Rectangle
 }
 
 // Image represents an image that can be restored when GL context is lost.
 type Image struct {
-	image  *graphics.Image
-	filter opengl.Filter
+	image     *graphics.Image
+	minFilter opengl.Filter
+	magFilter opengl.Filter
 
 	// baseImage and baseColor are exclusive.
 	basePixels       []uint8
@@ -45,31 +47,59 @@ type Image struct {
 	drawImageHistory []*drawImageHistoryItem
 	stale            bool
 
-	volatile bool
-	screen   bool
+	volatile  bool
+	screen    bool
+	exactSize bool
+	format    opengl.TextureFormat
 }
 
-func NewImage(width, height int, filter opengl.Filter, volatile bool) *Image {
+func NewImage(width, height int, minFilter, magFilter opengl.Filter, volatile bool, exactSize bool) *Image {
 	i := &Image{
-		image:    graphics.NewImage(width, height, filter),
-		filter:   filter,
-		volatile: volatile,
+		image:     graphics.NewImage(width, height, minFilter, magFilter, exactSize, opengl.TextureFormatRGBA8),
+		minFilter: minFilter,
+		magFilter: magFilter,
+		volatile:  volatile,
+		exactSize: exactSize,
 	}
 	theImages.add(i)
 	runtime.SetFinalizer(i, (*Image).Dispose)
 	return i
 }
 
-func NewImageFromImage(source *image.RGBA, width, height int, filter opengl.Filter) *Image {
-	w2, h2 := graphics.NextPowerOf2Int(width), graphics.NextPowerOf2Int(height)
+// NewFloatImage creates a volatile image with a floating point texture.
+//
+// The pixels of a floating point texture can't be saved without loss, so the image is always volatile.
+func NewFloatImage(width, height int, minFilter, magFilter opengl.Filter) *Image {
+	i := &Image{
+		image:     graphics.NewImage(width, height, minFilter, magFilter, false, opengl.TextureFormatRGBA16F),
+		minFilter: minFilter,
+		magFilter: magFilter,
+		volatile:  true,
+		format:    opengl.TextureFormatRGBA16F,
+	}
+	theImages.add(i)
+	runtime.SetFinalizer(i, (*Image).Dispose)
+	return i
+}
+
+// NewImageFromImage returns a new image with the pixels of source.
+//
+// source's size must be the texture size: the exact size if exactSize is true, or the size rounded up to a power of 2 otherwise.
+func NewImageFromImage(source *image.RGBA, width, height int, minFilter, magFilter opengl.Filter, exactSize bool) *Image {
+	w2, h2 := width, height
+	if !exactSize {
+		w2, h2 = graphics.NextPowerOf2Int(width), graphics.NextPowerOf2Int(height)
+	}
 	p := make([]uint8, 4*w2*h2)
 	for j := 0; j < height; j++ {
 		copy(p[j*w2*4:(j+1)*w2*4], source.Pix[j*source.Stride:])
 	}
 	i := &Image{
-		image:      graphics.NewImageFromImage(source, width, height, filter),
+		image:      graphics.NewImageFromImage(source, width, height, minFilter, magFilter, exactSize),
 		basePixels: p,
-		filter:     filter,
+		minFilter:  minFilter,
+		magFilter:  magFilter,
+		exactSize:  exactSize,
 	}
 	theImages.add(i)
 	runtime.SetFinalizer(i, (*Image).Dispose)
@@ -91,6 +121,11 @@ func (p *Image) Size() (int, int) {
 	return p.image.Size()
 }
 
+// TextureSize returns the size of the underlying texture.
+func (p *Image) TextureSize() (int, int) {
+	return p.image.TextureSize()
+}
+
 func (p *Image) makeStale() {
 	p.basePixels = nil
 	p.baseColor = color.RGBA{}
@@ -121,6 +156,20 @@ func (p *Image) Fill(clr color.RGBA) {
 	p.image.Fill(clr)
 }
 
+// SetFilter changes the filters of the image.
+//
+// The pixels are kept, and the new filters are also used when the image is restored.
+func (p *Image) SetFilter(minFilter, magFilter opengl.Filter) {
+	p.minFilter = minFilter
+	p.magFilter = magFilter
+	p.image.SetFilter(minFilter, magFilter)
+}
+
+// IsFloat returns a boolean value indicating whether the image has a floating point texture.
+func (p *Image) IsFloat() bool {
+	return p.format == opengl.TextureFormatRGBA16F
+}
+
 func (p *Image) ReplacePixels(pixels []uint8) {
 	theImages.resetPixelsIfDependingOn(p)
 	p.image.ReplacePixels(pixels)
@@ -130,17 +179,31 @@ func (p *Image) ReplacePixels(pixels []uint8) {
 	p.stale = false
 }
 
-func (p *Image) DrawImage(img *Image, vertices []float32, colorm affine.ColorM, mode opengl.CompositeMode) {
+// ReplacePixelsRegion replaces the pixels in the region r.
+//
+// pixels represents the whole pixels of the texture, and only the pixels in r are sent to the GPU.
+// pixels is kept as the base pixels to restore the image, so the pixels out of r must be
+// same as the pixels already sent or to be sent later by ReplacePixelsRegion.
+func (p *Image) ReplacePixelsRegion(pixels []uint8, r image.Rectangle) {
+	theImages.resetPixelsIfDependingOn(p)
+	p.image.ReplacePixelsRegion(pixels, r)
+	p.basePixels = pixels
+	p.baseColor = color.RGBA{}
+	p.drawImageHistory = nil
+	p.stale = false
+}
+
+func (p *Image) DrawImage(img *Image, vertices []float32, colorm affine.ColorM, mode opengl.CompositeMode, clip image.Rectangle) {
 	theImages.resetPixelsIfDependingOn(p)
 	if img.stale || img.volatile {
 		p.makeStale()
 	} else {
-		p.appendDrawImageHistory(img, vertices, colorm, mode)
+		p.appendDrawImageHistory(img, vertices, colorm, mode, clip)
 	}
-	p.image.DrawImage(img.image, vertices, colorm, mode)
+	p.image.DrawImage(img.image, vertices, colorm, mode, clip)
 }
 
-func (p *Image) appendDrawImageHistory(image *Image, vertices []float32, colorm affine.ColorM, mode opengl.CompositeMode) {
+func (p *Image) appendDrawImageHistory(image *Image, vertices []float32, colorm affine.ColorM, mode opengl.CompositeMode, clip image.Rectangle) {
 	if p.stale {
 		return
 	}
@@ -155,6 +218,7 @@ func (p *Image) appendDrawImageHistory(image *Image, vertices []float32, colorm
 		vertices: vertices,
 		colorm:   colorm,
 		mode:     mode,
+		clip:     clip,
 	}
 	p.drawImageHistory = append(p.drawImageHistory, item)
 }
@@ -164,8 +228,7 @@ func (p *Image) appendDrawImageHistory(image *Image, vertices []float32, colorm
 // Note that this must not be called until context is available.
 // This means Pixels members must match with acutal state in VRAM.
 func (p *Image) At(x, y int, context *opengl.Context) (color.RGBA, error) {
-	w, h := p.image.Size()
-	w2, h2 := graphics.NextPowerOf2Int(w), graphics.NextPowerOf2Int(h)
+	w2, h2 := p.image.TextureSize()
 	if x < 0 || y < 0 || w2 <= x || h2 <= y {
 		return color.RGBA{}, nil
 	}
@@ -179,6 +242,36 @@ func (p *Image) At(x, y int, context *opengl.Context) (color.RGBA, error) {
 	return color.RGBA{r, g, b, a}, nil
 }
 
+// Pixels returns the pixels of the image including the power-of-2 padding.
+//
+// The returned slice must not be modified.
+func (p *Image) Pixels(context *opengl.Context) ([]uint8, error) {
+	if p.basePixels == nil || p.drawImageHistory != nil || p.stale {
+		if err := p.readPixelsFromVRAM(p.image, context); err != nil {
+			return nil, err
+		}
+	}
+	return p.basePixels, nil
+}
+
+// SubPixels returns the pixels in the region r of the image.
+//
+// If the image's pixels are already cached, SubPixels copies them from the cache.
+// Otherwise, SubPixels reads only the region from VRAM without updating the cache.
+func (p *Image) SubPixels(r image.Rectangle, context *opengl.Context) ([]uint8, error) {
+	if p.basePixels == nil || p.drawImageHistory != nil || p.stale {
+		return p.image.SubPixels(context, r)
+	}
+	w2, _ := p.image.TextureSize()
+	w, h := r.Dx(), r.Dy()
+	pix := make([]uint8, 4*w*h)
+	for j := 0; j < h; j++ {
+		idx := 4*r.Min.X + 4*(r.Min.Y+j)*w2
+		copy(pix[4*j*w:4*(j+1)*w], p.basePixels[idx:idx+4*w])
+	}
+	return pix, nil
+}
+
 func (p *Image) makeStaleIfDependingOn(target *Image) {
 	if p.stale {
 		return
@@ -234,7 +327,7 @@ func (p *Image) restore(context *opengl.Context) error {
 		return nil
 	}
 	if p.volatile {
-		p.image = graphics.NewImage(w, h, p.filter)
+		p.image = graphics.NewImage(w, h, p.minFilter, p.magFilter, p.exactSize, p.format)
 		p.basePixels = nil
 		p.baseColor = color.RGBA{}
 		p.drawImageHistory = nil
@@ -245,14 +338,14 @@ func (p *Image) restore(context *opengl.Context) error {
 		// TODO: panic here?
 		return errors.New("restorable: pixels must not be stale when restoring")
 	}
-	w2, h2 := graphics.NextPowerOf2Int(w), graphics.NextPowerOf2Int(h)
+	w2, h2 := p.image.TextureSize()
 	img := image.NewRGBA(image.Rect(0, 0, w2, h2))
 	if p.basePixels != nil {
 		for j := 0; j < h; j++ {
 			copy(img.Pix[j*img.Stride:], p.basePixels[j*w2*4:(j+1)*w2*4])
 		}
 	}
-	gimg := graphics.NewImageFromImage(img, w, h, p.filter)
+	gimg := graphics.NewImageFromImage(img, w, h, p.minFilter, p.magFilter, p.exactSize)
 	if p.baseColor != (color.RGBA{}) {
 		if p.basePixels != nil {
 			panic("not reach")
@@ -264,7 +357,7 @@ func (p *Image) restore(context *opengl.Context) error {
 		if c.image.hasDependency() {
 			panic("not reach")
 		}
-		gimg.DrawImage(c.image.image, c.vertices, c.colorm, c.mode)
+		gimg.DrawImage(c.image.image, c.vertices, c.colorm, c.mode, c.clip)
 	}
 	p.image = gimg
 
@@ -38,8 +38,11 @@ func Restore(context *opengl.Context) error {
 	return theImages.restore(context)
 }
 
-func ClearVolatileImages() {
-	theImages.clearVolatileImages()
+// ClearVolatileImages clears all the volatile images except for the image except.
+//
+// except can be nil.
+func ClearVolatileImages(except *Image) {
+	theImages.clearVolatileImages(except)
 }
 
 func (i *images) add(img *Image) {
@@ -110,10 +113,13 @@ func (i *images) restore(context *opengl.Context) error {
 	return nil
 }
 
-func (i *images) clearVolatileImages() {
+func (i *images) clearVolatileImages(except *Image) {
 	i.m.Lock()
 	defer i.m.Unlock()
 	for img := range i.images {
+		if img == except {
+			continue
+		}
 		img.clearIfVolatile()
 	}
 }
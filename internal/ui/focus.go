@@ -0,0 +1,73 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"sync"
+)
+
+var (
+	focusCallback     func(focused bool)
+	focusHooks        []func(focused bool)
+	focused           = true
+	focusCallbackLock sync.Mutex
+)
+
+// SetFocusCallback sets the function called when the window gains or loses focus.
+//
+// If f is nil, the callback is removed.
+func SetFocusCallback(f func(focused bool)) {
+	focusCallbackLock.Lock()
+	focusCallback = f
+	focusCallbackLock.Unlock()
+}
+
+// AddFocusHook adds the function called when the window gains or loses focus.
+//
+// Unlike the callback set by SetFocusCallback, hooks can't be removed and don't replace each other.
+// This is for other packages like audio that must not overwrite the user's callback.
+// Hooks are called before the callback.
+func AddFocusHook(f func(focused bool)) {
+	focusCallbackLock.Lock()
+	focusHooks = append(focusHooks, f)
+	focusCallbackLock.Unlock()
+}
+
+// IsFocused returns true if the window is focused.
+func IsFocused() bool {
+	focusCallbackLock.Lock()
+	defer focusCallbackLock.Unlock()
+	return focused
+}
+
+// notifyFocus calls the focus callback only when the focus state is changed.
+func notifyFocus(f bool) {
+	focusCallbackLock.Lock()
+	if focused == f {
+		focusCallbackLock.Unlock()
+		return
+	}
+	focused = f
+	cb := focusCallback
+	hooks := focusHooks
+	focusCallbackLock.Unlock()
+
+	for _, h := range hooks {
+		h(f)
+	}
+	if cb != nil {
+		cb(f)
+	}
+}
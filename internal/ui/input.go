@@ -14,8 +14,21 @@
 
 package ui
 
+import (
+	"math"
+	"time"
+)
+
 var currentInput = &Input{}
 
+// toLogicalPosition converts the position (x, y) in the window or the canvas to the position in the logical screen.
+//
+// The result is floored so that positions slightly outside of the screen (e.g. x = -0.5) are
+// converted to positions outside of the screen (e.g. -1) instead of the edge of the screen.
+func toLogicalPosition(x, y float64, scaleX, scaleY float64) (int, int) {
+	return int(math.Floor(x / scaleX)), int(math.Floor(y / scaleY))
+}
+
 type Touch interface {
 	ID() int
 	Position() (x, y int)
@@ -31,6 +44,24 @@ func (i *Input) CursorPosition() (x, y int) {
 	return i.cursorX, i.cursorY
 }
 
+func (i *Input) CursorDelta() (dx, dy int) {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	return i.cursorDeltaX, i.cursorDeltaY
+}
+
+func (i *Input) GamepadIDs() []int {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	r := []int{}
+	for id, g := range i.gamepads {
+		if g.valid {
+			r = append(r, id)
+		}
+	}
+	return r
+}
+
 func (i *Input) GamepadAxisNum(id int) int {
 	i.m.RLock()
 	defer i.m.RUnlock()
@@ -58,6 +89,29 @@ func (i *Input) GamepadButtonNum(id int) int {
 	return i.gamepads[id].buttonNum
 }
 
+func (i *Input) GamepadName(id int) string {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if len(i.gamepads) <= id {
+		return ""
+	}
+	return i.gamepads[id].name
+}
+
+func (i *Input) IsStandardGamepadButtonPressed(id int, button StandardGamepadButton) bool {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if len(i.gamepads) <= id {
+		return false
+	}
+	g := &i.gamepads[id]
+	b, ok := g.standardToRawButton(button)
+	if !ok {
+		return false
+	}
+	return g.buttonPressed[b]
+}
+
 func (i *Input) IsGamepadButtonPressed(id int, button GamepadButton) bool {
 	i.m.RLock()
 	defer i.m.RUnlock()
@@ -67,6 +121,31 @@ func (i *Input) IsGamepadButtonPressed(id int, button GamepadButton) bool {
 	return i.gamepads[id].buttonPressed[button]
 }
 
+// TimeSinceLastInput returns the duration since the last input.
+//
+// Before any input, the duration is measured from the time when the game starts.
+func (i *Input) TimeSinceLastInput() time.Duration {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if i.lastInputTime.IsZero() {
+		return 0
+	}
+	return time.Since(i.lastInputTime)
+}
+
+// markInput records the current time as the time of the last input.
+// The caller must hold the lock.
+func (i *Input) markInput() {
+	i.lastInputTime = time.Now()
+}
+
+// resetLastInputTime is called when the game starts.
+func (i *Input) resetLastInputTime() {
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.markInput()
+}
+
 func (in *Input) Touches() []Touch {
 	in.m.RLock()
 	defer in.m.RUnlock()
@@ -78,10 +157,39 @@ func (in *Input) Touches() []Touch {
 }
 
 type gamePad struct {
+	valid         bool
 	axisNum       int
 	axes          [16]float64
 	buttonNum     int
 	buttonPressed [256]bool
+
+	// name is the name of the gamepad reported by the driver.
+	name string
+
+	// standard represents whether the buttons are already in the standard layout (e.g. by the browser).
+	standard bool
+}
+
+// gamepadAxisInputThreshold is the absolute axis value regarded as input.
+// Small values are ignored since axes might not be exactly 0 at the neutral position.
+const gamepadAxisInputThreshold = 0.5
+
+// hasInput returns a boolean value indicating whether any button is pressed or any axis is tilted.
+func (g *gamePad) hasInput() bool {
+	if !g.valid {
+		return false
+	}
+	for b := 0; b < g.buttonNum && b < len(g.buttonPressed); b++ {
+		if g.buttonPressed[b] {
+			return true
+		}
+	}
+	for a := 0; a < g.axisNum && a < len(g.axes); a++ {
+		if math.Abs(g.axes[a]) >= gamepadAxisInputThreshold {
+			return true
+		}
+	}
+	return false
 }
 
 type touch struct {
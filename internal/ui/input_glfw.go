@@ -21,6 +21,7 @@ package ui
 
 import (
 	"sync"
+	"time"
 
 	glfw "github.com/go-gl/glfw/v3.2/glfw"
 )
@@ -30,8 +31,12 @@ type Input struct {
 	mouseButtonPressed map[glfw.MouseButton]bool
 	cursorX            int
 	cursorY            int
+	cursorDeltaX       int
+	cursorDeltaY       int
+	cursorInitialized  bool
 	gamepads           [16]gamePad
 	touches            []touch
+	lastInputTime      time.Time
 	m                  sync.RWMutex
 }
 
@@ -69,35 +74,58 @@ func (i *Input) IsMouseButtonPressed(button MouseButton) bool {
 	return false
 }
 
+func (i *Input) VibrateGamepad(id int, strong, weak float64, duration time.Duration) {
+	// GLFW doesn't support vibration.
+}
+
 var glfwMouseButtonToMouseButton = map[glfw.MouseButton]MouseButton{
 	glfw.MouseButtonLeft:   MouseButtonLeft,
 	glfw.MouseButtonRight:  MouseButtonRight,
 	glfw.MouseButtonMiddle: MouseButtonMiddle,
 }
 
-func (i *Input) update(window *glfw.Window, scale float64) {
+func (i *Input) update(window *glfw.Window, scaleX, scaleY float64) {
 	i.m.Lock()
 	defer i.m.Unlock()
 
+	hasInput := false
 	if i.keyPressed == nil {
 		i.keyPressed = map[glfw.Key]bool{}
 	}
 	for gk := range glfwKeyCodeToKey {
 		i.keyPressed[gk] = window.GetKey(gk) == glfw.Press
+		if i.keyPressed[gk] {
+			hasInput = true
+		}
 	}
 	if i.mouseButtonPressed == nil {
 		i.mouseButtonPressed = map[glfw.MouseButton]bool{}
 	}
 	for gb := range glfwMouseButtonToMouseButton {
 		i.mouseButtonPressed[gb] = window.GetMouseButton(gb) == glfw.Press
+		if i.mouseButtonPressed[gb] {
+			hasInput = true
+		}
 	}
 	x, y := window.GetCursorPos()
-	i.cursorX = int(x / scale)
-	i.cursorY = int(y / scale)
+	cx, cy := toLogicalPosition(x, y, scaleX, scaleY)
+	if i.cursorInitialized {
+		i.cursorDeltaX = cx - i.cursorX
+		i.cursorDeltaY = cy - i.cursorY
+		if i.cursorDeltaX != 0 || i.cursorDeltaY != 0 {
+			hasInput = true
+		}
+	}
+	i.cursorX = cx
+	i.cursorY = cy
+	i.cursorInitialized = true
 	for id := glfw.Joystick(0); id < glfw.Joystick(len(i.gamepads)); id++ {
 		if !glfw.JoystickPresent(id) {
+			i.gamepads[id].valid = false
 			continue
 		}
+		i.gamepads[id].valid = true
+		i.gamepads[id].name = glfw.GetJoystickName(id)
 		axes32 := glfw.GetJoystickAxes(id)
 		i.gamepads[id].axisNum = len(axes32)
 		for a := 0; a < len(i.gamepads[id].axes); a++ {
@@ -116,5 +144,11 @@ func (i *Input) update(window *glfw.Window, scale float64) {
 			}
 			i.gamepads[id].buttonPressed[b] = glfw.Action(buttons[b]) == glfw.Press
 		}
+		if i.gamepads[id].hasInput() {
+			hasInput = true
+		}
+	}
+	if hasInput {
+		i.markInput()
 	}
 }
@@ -17,6 +17,8 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/gopherjs/gopherjs/js"
 )
 
@@ -33,8 +35,13 @@ type Input struct {
 	mouseButtonPressed map[int]bool
 	cursorX            int
 	cursorY            int
+	cursorDeltaX       int
+	cursorDeltaY       int
+	cursorMovementX    float64
+	cursorMovementY    float64
 	gamepads           [16]gamePad
 	touches            []touch
+	lastInputTime      time.Time
 	m                  mockRWLock
 }
 
@@ -85,6 +92,7 @@ func (i *Input) keyDown(code string) {
 		i.keyPressed = map[string]bool{}
 	}
 	i.keyPressed[code] = true
+	i.markInput()
 }
 
 func (i *Input) keyUp(code string) {
@@ -99,6 +107,7 @@ func (i *Input) keyDownSafari(code int) {
 		i.keyPressedSafari = map[int]bool{}
 	}
 	i.keyPressedSafari[code] = true
+	i.markInput()
 }
 
 func (i *Input) keyUpSafari(code int) {
@@ -113,6 +122,7 @@ func (i *Input) mouseDown(code int) {
 		i.mouseButtonPressed = map[int]bool{}
 	}
 	i.mouseButtonPressed[code] = true
+	i.markInput()
 }
 
 func (i *Input) mouseUp(code int) {
@@ -123,9 +133,53 @@ func (i *Input) mouseUp(code int) {
 }
 
 func (i *Input) setMouseCursor(x, y int) {
+	if i.cursorX != x || i.cursorY != y {
+		i.markInput()
+	}
 	i.cursorX, i.cursorY = x, y
 }
 
+func (i *Input) addCursorMovement(dx, dy float64) {
+	i.cursorMovementX += dx
+	i.cursorMovementY += dy
+	if dx != 0 || dy != 0 {
+		i.markInput()
+	}
+}
+
+func (i *Input) updateCursorDelta() {
+	// Keep the fractional parts for the next frame.
+	i.cursorDeltaX = int(i.cursorMovementX)
+	i.cursorDeltaY = int(i.cursorMovementY)
+	i.cursorMovementX -= float64(i.cursorDeltaX)
+	i.cursorMovementY -= float64(i.cursorDeltaY)
+}
+
+func (i *Input) VibrateGamepad(id int, strong, weak float64, duration time.Duration) {
+	nav := js.Global.Get("navigator")
+	if nav.Get("getGamepads") == js.Undefined {
+		return
+	}
+	gamepads := nav.Call("getGamepads")
+	if id < 0 || gamepads.Get("length").Int() <= id {
+		return
+	}
+	gamepad := gamepads.Index(id)
+	if gamepad == js.Undefined || gamepad == nil {
+		return
+	}
+	// vibrationActuator is available only on some browsers like Chrome.
+	a := gamepad.Get("vibrationActuator")
+	if a == js.Undefined || a == nil {
+		return
+	}
+	a.Call("playEffect", "dual-rumble", map[string]interface{}{
+		"duration":        float64(duration) / float64(time.Millisecond),
+		"strongMagnitude": strong,
+		"weakMagnitude":   weak,
+	})
+}
+
 func (i *Input) updateGamepads() {
 	nav := js.Global.Get("navigator")
 	if nav.Get("getGamepads") == js.Undefined {
@@ -133,11 +187,19 @@ func (i *Input) updateGamepads() {
 	}
 	gamepads := nav.Call("getGamepads")
 	l := gamepads.Get("length").Int()
-	for id := 0; id < l; id++ {
+	for id := 0; id < len(i.gamepads); id++ {
+		if l <= id {
+			i.gamepads[id].valid = false
+			continue
+		}
 		gamepad := gamepads.Index(id)
 		if gamepad == js.Undefined || gamepad == nil {
+			i.gamepads[id].valid = false
 			continue
 		}
+		i.gamepads[id].valid = true
+		i.gamepads[id].name = gamepad.Get("id").String()
+		i.gamepads[id].standard = gamepad.Get("mapping").String() == "standard"
 
 		axes := gamepad.Get("axes")
 		axesNum := axes.Get("length").Int()
@@ -160,10 +222,14 @@ func (i *Input) updateGamepads() {
 			}
 			i.gamepads[id].buttonPressed[b] = buttons.Index(b).Get("pressed").Bool()
 		}
+		if i.gamepads[id].hasInput() {
+			i.markInput()
+		}
 	}
 }
 
 func (i *Input) updateTouches(t []touch) {
 	i.touches = make([]touch, len(t))
 	copy(i.touches, t)
+	i.markInput()
 }
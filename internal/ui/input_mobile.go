@@ -18,14 +18,18 @@ package ui
 
 import (
 	"sync"
+	"time"
 )
 
 type Input struct {
-	cursorX  int
-	cursorY  int
-	gamepads [16]gamePad
-	touches  []touch
-	m        sync.RWMutex
+	cursorX       int
+	cursorY       int
+	cursorDeltaX  int
+	cursorDeltaY  int
+	gamepads      [16]gamePad
+	touches       []touch
+	lastInputTime time.Time
+	m             sync.RWMutex
 }
 
 func (i *Input) IsKeyPressed(key Key) bool {
@@ -36,6 +40,10 @@ func (i *Input) IsMouseButtonPressed(key MouseButton) bool {
 	return false
 }
 
+func (i *Input) VibrateGamepad(id int, strong, weak float64, duration time.Duration) {
+	// TODO: Implement this.
+}
+
 func (i *Input) updateTouches(touches []Touch) {
 	i.m.Lock()
 	defer i.m.Unlock()
@@ -46,4 +54,5 @@ func (i *Input) updateTouches(touches []Touch) {
 		ts[i].x, ts[i].y = x, y
 	}
 	i.touches = ts
+	i.markInput()
 }
@@ -0,0 +1,76 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+)
+
+func TestToLogicalPosition(t *testing.T) {
+	cases := []struct {
+		X      float64
+		Y      float64
+		ScaleX float64
+		ScaleY float64
+		WantX  int
+		WantY  int
+	}{
+		{0, 0, 1, 1, 0, 0},
+		{319.5, 239.5, 1, 1, 319, 239},
+		{10, 20, 2, 2, 5, 10},
+		{11, 21, 2, 2, 5, 10},
+		{15, 15, 1.5, 1.5, 10, 10},
+		{30, 10, 3, 1, 10, 10},
+		{-0.5, -0.5, 1, 1, -1, -1},
+		{-1, -1, 2, 2, -1, -1},
+		{640, 480, 2, 2, 320, 240},
+	}
+	for _, c := range cases {
+		x, y := toLogicalPosition(c.X, c.Y, c.ScaleX, c.ScaleY)
+		if x != c.WantX || y != c.WantY {
+			t.Errorf("toLogicalPosition(%v, %v, %v, %v): got (%d, %d), want: (%d, %d)", c.X, c.Y, c.ScaleX, c.ScaleY, x, y, c.WantX, c.WantY)
+		}
+	}
+}
+
+func TestStandardToRawButton(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Standard bool
+		Button   StandardGamepadButton
+		WantRaw  int
+		WantOK   bool
+	}{
+		{"Unknown", false, StandardGamepadButtonRightTop, 3, true},
+		{"Unknown", true, StandardGamepadButtonLeftRight, 15, true},
+		{"Xbox 360 Controller", false, StandardGamepadButtonLeftRight, 11, true},
+		{"Xbox 360 Controller", false, StandardGamepadButtonFrontBottomLeft, 0, false},
+		{"Microsoft X-Box 360 pad", false, StandardGamepadButtonCenterCenter, 8, true},
+		{"Xbox 360 Controller", true, StandardGamepadButtonLeftRight, 15, true},
+		{"Unknown", false, StandardGamepadButtonMax + 1, 0, false},
+	}
+	for _, c := range cases {
+		g := &gamePad{
+			valid:     true,
+			buttonNum: 16,
+			name:      c.Name,
+			standard:  c.Standard,
+		}
+		raw, ok := g.standardToRawButton(c.Button)
+		if raw != c.WantRaw || ok != c.WantOK {
+			t.Errorf("standardToRawButton(%d) for %q (standard: %v): got (%d, %v), want: (%d, %v)", c.Button, c.Name, c.Standard, raw, ok, c.WantRaw, c.WantOK)
+		}
+	}
+}
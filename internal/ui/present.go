@@ -0,0 +1,100 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var thePresentCallback atomic.Value
+
+type presentCallback func(t time.Time)
+
+// SetPresentCallback sets the function called every time a frame is presented.
+//
+// If f is nil, the callback is removed.
+func SetPresentCallback(f func(t time.Time)) {
+	thePresentCallback.Store(presentCallback(f))
+}
+
+var bufferCount = int32(3)
+
+// SetBufferCount sets the requested number of buffers used to present frames.
+//
+// n must be 2 or 3. With 2, the UI calls glFinish after swapping buffers.
+// With 3, the number of the buffers is driver-controlled.
+func SetBufferCount(n int) {
+	atomic.StoreInt32(&bufferCount, int32(n))
+}
+
+// BufferCount returns the number of buffers requested by SetBufferCount.
+func BufferCount() int {
+	return int(atomic.LoadInt32(&bufferCount))
+}
+
+// presentTiming is the measured timing of presenting frames.
+type presentTiming struct {
+	last     time.Time
+	interval time.Duration
+	m        sync.Mutex
+}
+
+var thePresentTiming presentTiming
+
+func (p *presentTiming) update(now time.Time) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	last := p.last
+	p.last = now
+	if last.IsZero() {
+		return
+	}
+	d := now.Sub(last)
+	// Ignore long intervals e.g. when the window is minimized.
+	if d <= 0 || 100*time.Millisecond < d {
+		return
+	}
+	if p.interval == 0 {
+		p.interval = d
+		return
+	}
+	// Exponential moving average to smooth jitter.
+	p.interval += (d - p.interval) / 20
+}
+
+// presentRate returns the measured number of presented frames per second.
+// presentRate returns 0 when the rate is not measured yet.
+func (p *presentTiming) presentRate() float64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.interval == 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(p.interval)
+}
+
+// notifyPresent calls the present callback with the current time.
+// notifyPresent must be called right after a frame is presented.
+func notifyPresent() {
+	now := time.Now()
+	thePresentTiming.update(now)
+	f, ok := thePresentCallback.Load().(presentCallback)
+	if !ok || f == nil {
+		return
+	}
+	f(now)
+}
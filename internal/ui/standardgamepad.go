@@ -0,0 +1,72 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+// StandardGamepadButton represents a button in the standard gamepad layout
+// defined by the W3C Gamepad API.
+type StandardGamepadButton int
+
+const (
+	StandardGamepadButtonRightBottom StandardGamepadButton = iota
+	StandardGamepadButtonRightRight
+	StandardGamepadButtonRightLeft
+	StandardGamepadButtonRightTop
+	StandardGamepadButtonFrontTopLeft
+	StandardGamepadButtonFrontTopRight
+	StandardGamepadButtonFrontBottomLeft
+	StandardGamepadButtonFrontBottomRight
+	StandardGamepadButtonCenterLeft
+	StandardGamepadButtonCenterRight
+	StandardGamepadButtonLeftStick
+	StandardGamepadButtonRightStick
+	StandardGamepadButtonLeftTop
+	StandardGamepadButtonLeftBottom
+	StandardGamepadButtonLeftLeft
+	StandardGamepadButtonLeftRight
+	StandardGamepadButtonCenterCenter
+	StandardGamepadButtonMax = StandardGamepadButtonCenterCenter
+)
+
+// standardGamepadMappings is the mapping database from gamepad names to the raw button indices
+// in the order of StandardGamepadButton. -1 means the button is not available as a button
+// (e.g. the D-pad reported as axes).
+//
+// GLFW 3.2 doesn't provide GUIDs of gamepads, so the names are used as the keys.
+var standardGamepadMappings = map[string][]int{
+	// XInput gamepads on Windows.
+	"Xbox 360 Controller": {0, 1, 2, 3, 4, 5, -1, -1, 6, 7, 8, 9, 10, 12, 13, 11, -1},
+	// Xbox 360 gamepads with the xpad driver on Linux. The triggers and the D-pad are reported as axes.
+	"Microsoft X-Box 360 pad": {0, 1, 2, 3, 4, 5, -1, -1, 6, 7, 9, 10, -1, -1, -1, -1, 8},
+}
+
+// standardToRawButton returns the raw button index for the standard button b.
+//
+// If the gamepad is already in the standard layout or is unknown, the raw index same as b is used.
+// ok is false when the button is not available on the gamepad.
+func (g *gamePad) standardToRawButton(b StandardGamepadButton) (raw int, ok bool) {
+	if b < 0 || StandardGamepadButtonMax < b {
+		return 0, false
+	}
+	raw = int(b)
+	if !g.standard {
+		if m, ok := standardGamepadMappings[g.name]; ok {
+			raw = m[b]
+		}
+	}
+	if raw < 0 || g.buttonNum <= raw || len(g.buttonPressed) <= raw {
+		return 0, false
+	}
+	return raw, true
+}
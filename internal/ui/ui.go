@@ -15,7 +15,7 @@
 package ui
 
 type GraphicsContext interface {
-	SetSize(width, height int, scale float64) error
+	SetSize(width, height int, scale float64, pixelAspectRatio float64) error
 	Update() error
 	Invalidate()
 }
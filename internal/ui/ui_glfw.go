@@ -30,14 +30,21 @@ import (
 )
 
 type userInterface struct {
-	window      *glfw.Window
-	width       int
-	height      int
-	scale       float64
-	funcs       chan func()
-	running     bool
-	sizeChanged bool
-	m           sync.Mutex
+	window           *glfw.Window
+	width            int
+	height           int
+	scale            float64
+	pixelAspectRatio float64
+	funcs            chan func()
+	running          bool
+	sizeChanged      bool
+	m                sync.Mutex
+
+	// cursors is the standard cursors created lazily. This must be accessed on the main thread.
+	cursors map[CursorShape]*glfw.Cursor
+
+	screenFramebuffer    uint32
+	useScreenFramebuffer bool
 }
 
 var currentUI *userInterface
@@ -66,9 +73,10 @@ func initialize() error {
 	}
 	hideConsoleWindowOnWindows()
 	u := &userInterface{
-		window:      window,
-		funcs:       make(chan func()),
-		sizeChanged: true,
+		window:           window,
+		pixelAspectRatio: 1,
+		funcs:            make(chan func()),
+		sizeChanged:      true,
 	}
 	u.window.MakeContextCurrent()
 	glfw.SwapInterval(1)
@@ -76,6 +84,17 @@ func initialize() error {
 	return nil
 }
 
+// SetScreenFramebuffer specifies the framebuffer object to render the screen into instead of the window.
+//
+// This must be called before Run.
+func SetScreenFramebuffer(fbo uint32) {
+	u := currentUI
+	u.m.Lock()
+	u.screenFramebuffer = fbo
+	u.useScreenFramebuffer = true
+	u.m.Unlock()
+}
+
 func RunMainThreadLoop(ch <-chan error) error {
 	// TODO: Check this is done on the main thread.
 	currentUI.setRunning(true)
@@ -127,7 +146,7 @@ func SetScreenSize(width, height int) bool {
 	}
 	r := false
 	_ = u.runOnMainThread(func() error {
-		u.setScreenSize(width, height, u.scale)
+		u.setScreenSize(width, height, u.scale, u.pixelAspectRatio)
 		return nil
 	})
 	return r
@@ -140,7 +159,7 @@ func SetScreenScale(scale float64) bool {
 	}
 	r := false
 	_ = u.runOnMainThread(func() error {
-		u.setScreenSize(u.width, u.height, scale)
+		u.setScreenSize(u.width, u.height, scale, u.pixelAspectRatio)
 		return nil
 	})
 	return r
@@ -159,6 +178,163 @@ func ScreenScale() float64 {
 	return s
 }
 
+func SetPixelAspectRatio(ratio float64) bool {
+	u := currentUI
+	if !u.isRunning() {
+		// The ratio is adopted when Run is called.
+		u.pixelAspectRatio = ratio
+		return true
+	}
+	r := false
+	_ = u.runOnMainThread(func() error {
+		r = u.setScreenSize(u.width, u.height, u.scale, ratio)
+		return nil
+	})
+	return r
+}
+
+func PixelAspectRatio() float64 {
+	u := currentUI
+	if !u.isRunning() {
+		return u.pixelAspectRatio
+	}
+	r := 0.0
+	_ = u.runOnMainThread(func() error {
+		r = u.pixelAspectRatio
+		return nil
+	})
+	return r
+}
+
+func FramebufferSize() (int, int) {
+	u := currentUI
+	if !u.isRunning() {
+		return 0, 0
+	}
+	w, h := 0, 0
+	_ = u.runOnMainThread(func() error {
+		w, h = u.window.GetFramebufferSize()
+		return nil
+	})
+	return w, h
+}
+
+func ScreenSizeInFullscreen() (int, int) {
+	u := currentUI
+	w, h := 0, 0
+	f := func() error {
+		v := glfw.GetPrimaryMonitor().GetVideoMode()
+		w = int(float64(v.Width) / glfwScale())
+		h = int(float64(v.Height) / glfwScale())
+		return nil
+	}
+	if !u.isRunning() {
+		// Before Run, this must be called on the main thread.
+		_ = f()
+		return w, h
+	}
+	_ = u.runOnMainThread(f)
+	return w, h
+}
+
+func DisplayRefreshRate() float64 {
+	u := currentUI
+	r := 0
+	f := func() error {
+		if v := u.currentMonitor().GetVideoMode(); v != nil {
+			r = v.RefreshRate
+		}
+		return nil
+	}
+	if !u.isRunning() {
+		// Before Run, this must be called on the main thread.
+		_ = f()
+		return float64(r)
+	}
+	_ = u.runOnMainThread(f)
+	return float64(r)
+}
+
+func DisplayColorSpace() ColorSpace {
+	// GLFW doesn't expose the color capabilities of monitors. Assume sRGB.
+	return ColorSpaceSRGB
+}
+
+func IsVsyncEffective() bool {
+	// GLFW can't report whether the driver honors the swap interval.
+	// Estimate it from the frame cadence: without vsync, frames are presented more often than
+	// the display refreshes.
+	rate := thePresentTiming.presentRate()
+	if rate == 0 {
+		// Not measured yet. Assume vsync works as requested.
+		return true
+	}
+	refreshRate := DisplayRefreshRate()
+	if refreshRate == 0 {
+		refreshRate = 60
+	}
+	return rate <= refreshRate*1.1
+}
+
+// currentMonitor returns the monitor that contains the center of the window.
+//
+// currentMonitor must be called on the main thread.
+func (u *userInterface) currentMonitor() *glfw.Monitor {
+	if m := u.window.GetMonitor(); m != nil {
+		return m
+	}
+	x, y := u.window.GetPos()
+	w, h := u.window.GetSize()
+	cx, cy := x+w/2, y+h/2
+	for _, m := range glfw.GetMonitors() {
+		mx, my := m.GetPos()
+		v := m.GetVideoMode()
+		if v == nil {
+			continue
+		}
+		if mx <= cx && cx < mx+v.Width && my <= cy && cy < my+v.Height {
+			return m
+		}
+	}
+	return glfw.GetPrimaryMonitor()
+}
+
+func ClipboardText() (string, error) {
+	u := currentUI
+	s := ""
+	f := func() error {
+		// GetClipboardString returns an error when the clipboard is empty or doesn't contain text.
+		// Treat such cases as an empty string.
+		str, err := u.window.GetClipboardString()
+		if err != nil {
+			return nil
+		}
+		s = str
+		return nil
+	}
+	if !u.isRunning() {
+		// Before Run, this must be called on the main thread.
+		_ = f()
+		return s, nil
+	}
+	_ = u.runOnMainThread(f)
+	return s, nil
+}
+
+func SetClipboardText(text string) {
+	u := currentUI
+	f := func() error {
+		u.window.SetClipboardString(text)
+		return nil
+	}
+	if !u.isRunning() {
+		// Before Run, this must be called on the main thread.
+		_ = f()
+		return
+	}
+	_ = u.runOnMainThread(f)
+}
+
 func SetCursorVisibility(visible bool) {
 	// This can be called before Run: change the state asyncly.
 	go func() {
@@ -173,8 +349,71 @@ func SetCursorVisibility(visible bool) {
 	}()
 }
 
+var glfwStandardCursors = map[CursorShape]glfw.StandardCursor{
+	CursorShapeText:      glfw.IBeamCursor,
+	CursorShapeCrosshair: glfw.CrosshairCursor,
+	CursorShapePointer:   glfw.HandCursor,
+	CursorShapeEWResize:  glfw.HResizeCursor,
+	CursorShapeNSResize:  glfw.VResizeCursor,
+}
+
+func SetCursorShape(shape CursorShape) {
+	// This can be called before Run: change the state asyncly.
+	go func() {
+		_ = currentUI.runOnMainThread(func() error {
+			u := currentUI
+			if shape == CursorShapeDefault {
+				u.window.SetCursor(nil)
+				return nil
+			}
+			c, ok := u.cursors[shape]
+			if !ok {
+				c = glfw.CreateStandardCursor(glfwStandardCursors[shape])
+				if u.cursors == nil {
+					u.cursors = map[CursorShape]*glfw.Cursor{}
+				}
+				u.cursors[shape] = c
+			}
+			u.window.SetCursor(c)
+			return nil
+		})
+	}()
+}
+
+func SetCursorMode(mode CursorMode) {
+	// This can be called before Run: change the state asyncly.
+	go func() {
+		_ = currentUI.runOnMainThread(func() error {
+			c := glfw.CursorNormal
+			switch mode {
+			case CursorModeHidden:
+				c = glfw.CursorHidden
+			case CursorModeCaptured:
+				c = glfw.CursorDisabled
+			}
+			currentUI.window.SetInputMode(glfw.CursorMode, c)
+			return nil
+		})
+	}()
+}
+
+func SetBorderColor(r, g, b uint8) {
+	// Do nothing: the screen framebuffer is filled with the border color by the graphics context.
+}
+
+func SetWindowFloating(floating bool) {
+	// This can be called before Run: change the state asyncly.
+	go func() {
+		_ = currentUI.runOnMainThread(func() error {
+			setWindowFloating(currentUI.window, floating)
+			return nil
+		})
+	}()
+}
+
 func Run(width, height int, scale float64, title string, g GraphicsContext) error {
 	u := currentUI
+	currentInput.resetLastInputTime()
 	// GLContext must be created before setting the screen size, which requires
 	// swapping buffers.
 	var err error
@@ -182,10 +421,15 @@ func Run(width, height int, scale float64, title string, g GraphicsContext) erro
 	if err != nil {
 		return err
 	}
+	u.m.Lock()
+	if u.useScreenFramebuffer {
+		glContext.SetScreenFramebuffer(u.screenFramebuffer)
+	}
+	u.m.Unlock()
 	if err := u.runOnMainThread(func() error {
 		m := glfw.GetPrimaryMonitor()
 		v := m.GetVideoMode()
-		if !u.setScreenSize(width, height, scale) {
+		if !u.setScreenSize(width, height, scale, u.pixelAspectRatio) {
 			return errors.New("ui: Fail to set the screen size")
 		}
 		u.window.SetTitle(title)
@@ -204,7 +448,7 @@ func Run(width, height int, scale float64, title string, g GraphicsContext) erro
 }
 
 func (u *userInterface) glfwSize() (int, int) {
-	return int(float64(u.width) * u.scale * glfwScale()), int(float64(u.height) * u.scale * glfwScale())
+	return int(float64(u.width) * u.scale * u.pixelAspectRatio * glfwScale()), int(float64(u.height) * u.scale * glfwScale())
 }
 
 func (u *userInterface) actualScreenScale() float64 {
@@ -213,7 +457,7 @@ func (u *userInterface) actualScreenScale() float64 {
 
 func (u *userInterface) pollEvents() {
 	glfw.PollEvents()
-	currentInput.update(u.window, u.scale*glfwScale())
+	currentInput.update(u.window, u.scale*u.pixelAspectRatio*glfwScale(), u.scale*glfwScale())
 }
 
 func (u *userInterface) update(g GraphicsContext) error {
@@ -227,32 +471,45 @@ func (u *userInterface) update(g GraphicsContext) error {
 	}
 
 	actualScale := 0.0
+	pixelAspectRatio := 0.0
 	_ = u.runOnMainThread(func() error {
 		if !u.sizeChanged {
 			return nil
 		}
 		u.sizeChanged = false
 		actualScale = u.actualScreenScale()
+		pixelAspectRatio = u.pixelAspectRatio
 		return nil
 	})
 	if 0 < actualScale {
-		if err := g.SetSize(u.width, u.height, actualScale); err != nil {
+		if err := g.SetSize(u.width, u.height, actualScale, pixelAspectRatio); err != nil {
 			return err
 		}
 	}
 
+	focused := true
 	_ = u.runOnMainThread(func() error {
 		u.pollEvents()
-		for u.window.GetAttrib(glfw.Focused) == 0 {
-			// Wait for an arbitrary period to avoid busy loop.
-			time.Sleep(time.Second / 60)
-			u.pollEvents()
-			if u.window.ShouldClose() {
-				return nil
-			}
-		}
+		focused = u.window.GetAttrib(glfw.Focused) != 0
 		return nil
 	})
+	// Call the callback outside of the main thread so that the callback can call any functions.
+	notifyFocus(focused)
+	if !focused {
+		_ = u.runOnMainThread(func() error {
+			for u.window.GetAttrib(glfw.Focused) == 0 {
+				// Wait for an arbitrary period to avoid busy loop.
+				time.Sleep(time.Second / 60)
+				u.pollEvents()
+				if u.window.ShouldClose() {
+					return nil
+				}
+			}
+			focused = true
+			return nil
+		})
+		notifyFocus(focused)
+	}
 	if err := g.Update(); err != nil {
 		return err
 	}
@@ -278,6 +535,11 @@ func (u *userInterface) loop(g GraphicsContext) error {
 			u.swapBuffers()
 			return nil
 		})
+		if BufferCount() == 2 {
+			// Wait for the swap so that the driver doesn't queue another frame ahead.
+			glContext.Finish()
+		}
+		notifyPresent()
 	}
 }
 
@@ -285,21 +547,24 @@ func (u *userInterface) swapBuffers() {
 	u.window.SwapBuffers()
 }
 
-func (u *userInterface) setScreenSize(width, height int, scale float64) bool {
-	if u.width == width && u.height == height && u.scale == scale {
+func (u *userInterface) setScreenSize(width, height int, scale float64, pixelAspectRatio float64) bool {
+	if u.width == width && u.height == height && u.scale == scale && u.pixelAspectRatio == pixelAspectRatio {
 		return false
 	}
 
 	origScale := u.scale
+	origPixelAspectRatio := u.pixelAspectRatio
 	u.scale = scale
+	u.pixelAspectRatio = pixelAspectRatio
 
 	// On Windows, giving a too small width doesn't call a callback (#165).
 	// To prevent hanging up, return asap if the width is too small.
 	// 252 is an arbitrary number and I guess this is small enough.
 	// TODO: The same check should be in ui_js.go
 	const minWindowWidth = 252
-	if int(float64(width)*u.actualScreenScale()) < minWindowWidth {
+	if int(float64(width)*u.actualScreenScale()*u.pixelAspectRatio) < minWindowWidth {
 		u.scale = origScale
+		u.pixelAspectRatio = origPixelAspectRatio
 		return false
 	}
 	u.width = width
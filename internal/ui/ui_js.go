@@ -17,6 +17,7 @@
 package ui
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
@@ -27,15 +28,23 @@ import (
 var canvas *js.Object
 
 type userInterface struct {
-	scale       float64
-	deviceScale float64
-	sizeChanged bool
-	windowFocus bool
+	scale            float64
+	pixelAspectRatio float64
+	deviceScale      float64
+	sizeChanged      bool
+	windowFocus      bool
+	cursorMode       CursorMode
+	cursorShape      CursorShape
+
+	// lastFrameTime and frameInterval are in milliseconds and used to estimate the refresh rate.
+	lastFrameTime float64
+	frameInterval float64
 }
 
 var currentUI = &userInterface{
-	sizeChanged: true,
-	windowFocus: true,
+	pixelAspectRatio: 1,
+	sizeChanged:      true,
+	windowFocus:      true,
 }
 
 // NOTE: This returns true even when the browser is not active.
@@ -43,27 +52,177 @@ func shown() bool {
 	return !js.Global.Get("document").Get("hidden").Bool()
 }
 
+// SetScreenFramebuffer does nothing on browsers, where WebGL framebuffers are not integer handles.
+func SetScreenFramebuffer(fbo uint32) {
+}
+
 func SetScreenSize(width, height int) bool {
-	return currentUI.setScreenSize(width, height, currentUI.scale)
+	return currentUI.setScreenSize(width, height, currentUI.scale, currentUI.pixelAspectRatio)
 }
 
 func SetScreenScale(scale float64) bool {
 	width, height := currentUI.size()
-	return currentUI.setScreenSize(width, height, scale)
+	return currentUI.setScreenSize(width, height, scale, currentUI.pixelAspectRatio)
 }
 
 func ScreenScale() float64 {
 	return currentUI.scale
 }
 
+func SetPixelAspectRatio(ratio float64) bool {
+	u := currentUI
+	if u.scale == 0 {
+		// Run is not called yet. The ratio is adopted when Run is called.
+		u.pixelAspectRatio = ratio
+		return true
+	}
+	width, height := u.size()
+	return u.setScreenSize(width, height, u.scale, ratio)
+}
+
+func PixelAspectRatio() float64 {
+	return currentUI.pixelAspectRatio
+}
+
+func FramebufferSize() (int, int) {
+	return canvas.Get("width").Int(), canvas.Get("height").Int()
+}
+
+func ScreenSizeInFullscreen() (int, int) {
+	window := js.Global.Get("window")
+	return window.Get("innerWidth").Int(), window.Get("innerHeight").Int()
+}
+
+func DisplayRefreshRate() float64 {
+	// Browsers don't provide the refresh rate. Estimate it from the intervals of animation frames.
+	if currentUI.frameInterval == 0 {
+		return 0
+	}
+	return 1000 / currentUI.frameInterval
+}
+
+func DisplayColorSpace() ColorSpace {
+	matchMedia := js.Global.Get("matchMedia")
+	if matchMedia == js.Undefined {
+		return ColorSpaceSRGB
+	}
+	// Unknown media queries never match, so old browsers fall back to sRGB.
+	if js.Global.Call("matchMedia", "(dynamic-range: high)").Get("matches").Bool() {
+		return ColorSpaceHDR
+	}
+	if js.Global.Call("matchMedia", "(color-gamut: p3)").Get("matches").Bool() {
+		return ColorSpaceWideGamut
+	}
+	return ColorSpaceSRGB
+}
+
+func IsVsyncEffective() bool {
+	// Frames are always presented by requestAnimationFrame, which syncs with the display.
+	return true
+}
+
+func (u *userInterface) updateFrameInterval() {
+	now := js.Global.Get("performance").Call("now").Float()
+	last := u.lastFrameTime
+	u.lastFrameTime = now
+	if last == 0 {
+		return
+	}
+	d := now - last
+	// Ignore long intervals e.g. when the page is hidden.
+	if d <= 0 || 100 < d {
+		return
+	}
+	if u.frameInterval == 0 {
+		u.frameInterval = d
+		return
+	}
+	// Exponential moving average to smooth jitter.
+	u.frameInterval += (d - u.frameInterval) * 0.05
+}
+
+func ClipboardText() (string, error) {
+	// The clipboard can be read only asynchronously with the user's permission on browsers.
+	return "", errors.New("ui: reading the clipboard is not supported on browsers")
+}
+
+func SetClipboardText(text string) {
+	clipboard := js.Global.Get("navigator").Get("clipboard")
+	if clipboard == js.Undefined || clipboard == nil {
+		return
+	}
+	// writeText returns a promise. Ignore the result since this might fail without the user's gesture.
+	clipboard.Call("writeText", text)
+}
+
+var cssCursors = map[CursorShape]string{
+	CursorShapeDefault:   "auto",
+	CursorShapeText:      "text",
+	CursorShapeCrosshair: "crosshair",
+	CursorShapePointer:   "pointer",
+	CursorShapeEWResize:  "ew-resize",
+	CursorShapeNSResize:  "ns-resize",
+}
+
+func SetCursorShape(shape CursorShape) {
+	currentUI.cursorShape = shape
+	if canvas.Get("style").Get("cursor").String() == "none" {
+		// The cursor is hidden. The shape is applied when the cursor is shown.
+		return
+	}
+	canvas.Get("style").Set("cursor", cssCursors[shape])
+}
+
 func SetCursorVisibility(visibility bool) {
 	if visibility {
-		canvas.Get("style").Set("cursor", "auto")
+		canvas.Get("style").Set("cursor", cssCursors[currentUI.cursorShape])
 	} else {
 		canvas.Get("style").Set("cursor", "none")
 	}
 }
 
+func SetCursorMode(mode CursorMode) {
+	currentUI.cursorMode = mode
+	switch mode {
+	case CursorModeVisible:
+		canvas.Get("style").Set("cursor", cssCursors[currentUI.cursorShape])
+	case CursorModeHidden, CursorModeCaptured:
+		canvas.Get("style").Set("cursor", "none")
+	}
+	if mode == CursorModeCaptured {
+		requestPointerLock()
+		return
+	}
+	doc := js.Global.Get("document")
+	if doc.Get("pointerLockElement") == canvas && doc.Get("exitPointerLock") != js.Undefined {
+		doc.Call("exitPointerLock")
+	}
+}
+
+func requestPointerLock() {
+	if canvas.Get("requestPointerLock") == js.Undefined {
+		return
+	}
+	if js.Global.Get("document").Get("pointerLockElement") == canvas {
+		return
+	}
+	// This might fail without a user gesture. In this case, the cursor is locked at the next click.
+	canvas.Call("requestPointerLock")
+}
+
+func SetWindowFloating(floating bool) {
+	// Do nothing
+}
+
+func SetBorderColor(r, g, b uint8) {
+	// Do nothing in node.js.
+	if js.Global.Get("require") != js.Undefined {
+		return
+	}
+	c := "rgb(" + strconv.Itoa(int(r)) + ", " + strconv.Itoa(int(g)) + ", " + strconv.Itoa(int(b)) + ")"
+	js.Global.Get("document").Get("body").Get("style").Set("backgroundColor", c)
+}
+
 func (u *userInterface) actualScreenScale() float64 {
 	return u.scale * u.deviceScale
 }
@@ -77,10 +236,11 @@ func (u *userInterface) update(g GraphicsContext) error {
 		g.Invalidate()
 	}
 	currentInput.updateGamepads()
+	currentInput.updateCursorDelta()
 	if u.sizeChanged {
 		u.sizeChanged = false
 		w, h := u.size()
-		if err := g.SetSize(w, h, u.actualScreenScale()); err != nil {
+		if err := g.SetSize(w, h, u.actualScreenScale(), u.pixelAspectRatio); err != nil {
 			return err
 		}
 		return nil
@@ -94,7 +254,14 @@ func (u *userInterface) update(g GraphicsContext) error {
 func (u *userInterface) loop(g GraphicsContext) error {
 	ch := make(chan error)
 	var f func()
+	first := true
 	f = func() {
+		// The browser presents the previous frame before calling the animation frame callback.
+		if !first {
+			notifyPresent()
+		}
+		first = false
+		u.updateFrameInterval()
 		go func() {
 			if err := u.update(g); err != nil {
 				ch <- err
@@ -108,17 +275,28 @@ func (u *userInterface) loop(g GraphicsContext) error {
 	return <-ch
 }
 
+// canvasScale returns the scales from the logical screen to the canvas's bounding rect.
+//
+// The scales are calculated from the actual rect instead of the screen scale
+// so that positions are correct even when the canvas is resized by CSS.
+func (u *userInterface) canvasScale(rect *js.Object) (float64, float64) {
+	w, h := u.size()
+	if w == 0 || h == 0 {
+		return u.scale * u.pixelAspectRatio, u.scale
+	}
+	return rect.Get("width").Float() / float64(w), rect.Get("height").Float() / float64(h)
+}
+
 func touchEventToTouches(e *js.Object) []touch {
-	scale := currentUI.scale
 	j := e.Get("targetTouches")
 	rect := canvas.Call("getBoundingClientRect")
-	left, top := rect.Get("left").Int(), rect.Get("top").Int()
+	scaleX, scaleY := currentUI.canvasScale(rect)
+	left, top := rect.Get("left").Float(), rect.Get("top").Float()
 	t := make([]touch, j.Get("length").Int())
 	for i := 0; i < len(t); i++ {
 		jj := j.Call("item", i)
 		t[i].id = jj.Get("identifier").Int()
-		t[i].x = int(float64(jj.Get("clientX").Int()-left) / scale)
-		t[i].y = int(float64(jj.Get("clientY").Int()-top) / scale)
+		t[i].x, t[i].y = toLogicalPosition(jj.Get("clientX").Float()-left, jj.Get("clientY").Float()-top, scaleX, scaleY)
 	}
 	return t
 }
@@ -146,9 +324,11 @@ func initialize() error {
 	}
 	window.Call("addEventListener", "focus", func() {
 		currentUI.windowFocus = true
+		notifyFocus(true)
 	})
 	window.Call("addEventListener", "blur", func() {
 		currentUI.windowFocus = false
+		notifyFocus(false)
 	})
 
 	canvas = doc.Call("createElement", "canvas")
@@ -209,6 +389,9 @@ func initialize() error {
 		button := e.Get("button").Int()
 		currentInput.mouseDown(button)
 		setMouseCursorFromEvent(e)
+		if currentUI.cursorMode == CursorModeCaptured {
+			requestPointerLock()
+		}
 	})
 	canvas.Call("addEventListener", "mouseup", func(e *js.Object) {
 		e.Call("preventDefault")
@@ -219,6 +402,11 @@ func initialize() error {
 	canvas.Call("addEventListener", "mousemove", func(e *js.Object) {
 		e.Call("preventDefault")
 		setMouseCursorFromEvent(e)
+		if e.Get("movementX") != js.Undefined {
+			scaleX := currentUI.scale * currentUI.pixelAspectRatio
+			scaleY := currentUI.scale
+			currentInput.addCursorMovement(e.Get("movementX").Float()/scaleX, e.Get("movementY").Float()/scaleY)
+		}
 	})
 	canvas.Call("addEventListener", "contextmenu", func(e *js.Object) {
 		e.Call("preventDefault")
@@ -254,12 +442,11 @@ func initialize() error {
 }
 
 func setMouseCursorFromEvent(e *js.Object) {
-	scale := currentUI.scale
 	rect := canvas.Call("getBoundingClientRect")
-	x, y := e.Get("clientX").Int(), e.Get("clientY").Int()
-	x -= rect.Get("left").Int()
-	y -= rect.Get("top").Int()
-	currentInput.setMouseCursor(int(float64(x)/scale), int(float64(y)/scale))
+	scaleX, scaleY := currentUI.canvasScale(rect)
+	x := e.Get("clientX").Float() - rect.Get("left").Float()
+	y := e.Get("clientY").Float() - rect.Get("top").Float()
+	currentInput.setMouseCursor(toLogicalPosition(x, y, scaleX, scaleY))
 }
 
 func devicePixelRatio() float64 {
@@ -276,9 +463,10 @@ func RunMainThreadLoop(ch <-chan error) error {
 
 func Run(width, height int, scale float64, title string, g GraphicsContext) error {
 	u := currentUI
+	currentInput.resetLastInputTime()
 	doc := js.Global.Get("document")
 	doc.Set("title", title)
-	u.setScreenSize(width, height, scale)
+	u.setScreenSize(width, height, scale, u.pixelAspectRatio)
 	canvas.Call("focus")
 	var err error
 	glContext, err = opengl.NewContext()
@@ -294,7 +482,7 @@ func (u *userInterface) size() (width, height int) {
 		// a == 0 only on the initial state.
 		return
 	}
-	width = int(canvas.Get("width").Float() / a)
+	width = int(canvas.Get("width").Float() / a / u.pixelAspectRatio)
 	height = int(canvas.Get("height").Float() / a)
 	return
 }
@@ -310,24 +498,25 @@ func isSafari() bool {
 	return true
 }
 
-func (u *userInterface) setScreenSize(width, height int, scale float64) bool {
+func (u *userInterface) setScreenSize(width, height int, scale float64, pixelAspectRatio float64) bool {
 	w, h := u.size()
 	s := u.scale
-	if w == width && h == height && s == scale {
+	if w == width && h == height && s == scale && u.pixelAspectRatio == pixelAspectRatio {
 		return false
 	}
 	u.scale = scale
+	u.pixelAspectRatio = pixelAspectRatio
 	// CSS imageRendering seems useful to enlarge the screen,
 	// but doesn't work in some cases (#306):
 	// * Chrome just after restoring the lost context
 	// * Safari
 	// Let's use the pixel ratio as it is here.
 	u.deviceScale = devicePixelRatio()
-	canvas.Set("width", int(float64(width)*u.actualScreenScale()))
+	canvas.Set("width", int(float64(width)*u.actualScreenScale()*pixelAspectRatio))
 	canvas.Set("height", int(float64(height)*u.actualScreenScale()))
 	canvasStyle := canvas.Get("style")
 
-	cssWidth := int(float64(width) * scale)
+	cssWidth := int(float64(width) * scale * pixelAspectRatio)
 	cssHeight := int(float64(height) * scale)
 	canvasStyle.Set("width", strconv.Itoa(cssWidth)+"px")
 	canvasStyle.Set("height", strconv.Itoa(cssHeight)+"px")
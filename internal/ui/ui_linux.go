@@ -17,6 +17,10 @@
 
 package ui
 
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
 func deviceScale() float64 {
 	// TODO: Implement this
 	return 1
@@ -29,3 +33,7 @@ func glfwScale() float64 {
 func adjustWindowPosition(x, y int) (int, int) {
 	return x, y
 }
+
+func setWindowFloating(window *glfw.Window, floating bool) {
+	// TODO: Implement this (_NET_WM_STATE_ABOVE on X11).
+}
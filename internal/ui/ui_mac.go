@@ -27,8 +27,17 @@ package ui
 //   NSScreen* primary = [[NSScreen screens] firstObject];
 //   return [primary backingScaleFactor];
 // }
+//
+// static void setWindowFloating(uintptr_t window, int floating) {
+//   NSInteger level = floating ? NSFloatingWindowLevel : NSNormalWindowLevel;
+//   [(NSWindow*)window setLevel:level];
+// }
 import "C"
 
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
 func deviceScale() float64 {
 	return float64(C.scale())
 }
@@ -40,3 +49,11 @@ func glfwScale() float64 {
 func adjustWindowPosition(x, y int) (int, int) {
 	return x, y
 }
+
+func setWindowFloating(window *glfw.Window, floating bool) {
+	f := C.int(0)
+	if floating {
+		f = 1
+	}
+	C.setWindowFloating(C.uintptr_t(window.GetCocoaWindow()), f)
+}
@@ -50,6 +50,9 @@ type userInterface struct {
 	height      int
 	scale       float64
 	sizeChanged bool
+
+	screenFramebuffer    uint32
+	useScreenFramebuffer bool
 }
 
 var (
@@ -62,6 +65,7 @@ var (
 
 func Run(width, height int, scale float64, title string, g GraphicsContext) error {
 	u := currentUI
+	currentInput.resetLastInputTime()
 	u.width = width
 	u.height = height
 	u.scale = scale
@@ -71,6 +75,9 @@ func Run(width, height int, scale float64, title string, g GraphicsContext) erro
 	if err != nil {
 		return err
 	}
+	if u.useScreenFramebuffer {
+		glContext.SetScreenFramebuffer(u.screenFramebuffer)
+	}
 	for {
 		if err := u.update(g); err != nil {
 			return err
@@ -82,12 +89,13 @@ func (u *userInterface) update(g GraphicsContext) error {
 	<-chRender
 	defer func() {
 		chRenderEnd <- struct{}{}
+		notifyPresent()
 	}()
 
 	if u.sizeChanged {
 		// Sizing also calls GL functions
 		u.sizeChanged = false
-		if err := g.SetSize(u.width, u.height, u.actualScreenScale()); err != nil {
+		if err := g.SetSize(u.width, u.height, u.actualScreenScale(), 1); err != nil {
 			return err
 		}
 		return nil
@@ -98,6 +106,14 @@ func (u *userInterface) update(g GraphicsContext) error {
 	return nil
 }
 
+// SetScreenFramebuffer specifies the framebuffer object to render the screen into.
+//
+// This must be called before Run.
+func SetScreenFramebuffer(fbo uint32) {
+	currentUI.screenFramebuffer = fbo
+	currentUI.useScreenFramebuffer = true
+}
+
 func SetScreenSize(width, height int) bool {
 	// TODO: Implement
 	return false
@@ -112,10 +128,70 @@ func ScreenScale() float64 {
 	return currentUI.scale
 }
 
+func SetPixelAspectRatio(ratio float64) bool {
+	// TODO: Implement
+	return false
+}
+
+func PixelAspectRatio() float64 {
+	return 1
+}
+
+func FramebufferSize() (int, int) {
+	u := currentUI
+	s := u.actualScreenScale()
+	return int(float64(u.width) * s), int(float64(u.height) * s)
+}
+
+func ScreenSizeInFullscreen() (int, int) {
+	// TODO: Implement
+	return 0, 0
+}
+
+func DisplayRefreshRate() float64 {
+	// TODO: Implement
+	return 0
+}
+
+func DisplayColorSpace() ColorSpace {
+	// TODO: Query the screen's color gamut (e.g. Display.isWideColorGamut on Android).
+	return ColorSpaceSRGB
+}
+
+func IsVsyncEffective() bool {
+	// Frames are always presented in sync with the display on mobiles.
+	return true
+}
+
+func ClipboardText() (string, error) {
+	// TODO: Implement
+	return "", errors.New("ui: reading the clipboard is not supported on mobiles")
+}
+
+func SetClipboardText(text string) {
+	// TODO: Implement
+}
+
 func SetCursorVisibility(visibility bool) {
 	// Do nothing
 }
 
+func SetCursorShape(shape CursorShape) {
+	// Do nothing
+}
+
+func SetCursorMode(mode CursorMode) {
+	// Do nothing
+}
+
+func SetWindowFloating(floating bool) {
+	// Do nothing
+}
+
+func SetBorderColor(r, g, b uint8) {
+	// Do nothing
+}
+
 func (u *userInterface) actualScreenScale() float64 {
 	return u.scale * deviceScale()
 }
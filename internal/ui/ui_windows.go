@@ -35,8 +35,19 @@ package ui
 //   return GetSystemMetrics(SM_CYCAPTION);
 // }
 //
+// static void setWindowFloating(void* hwnd, int floating) {
+//   HWND after = floating ? HWND_TOPMOST : HWND_NOTOPMOST;
+//   SetWindowPos((HWND)hwnd, after, 0, 0, 0, 0, SWP_NOMOVE | SWP_NOSIZE | SWP_NOACTIVATE);
+// }
+//
 import "C"
 
+import (
+	"unsafe"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
 func deviceScale() float64 {
 	dpi := C.int(0)
 	if errmsg := C.GoString(C.getDPI(&dpi)); errmsg != "" {
@@ -61,3 +72,11 @@ func adjustWindowPosition(x, y int) (int, int) {
 	}
 	return x, y
 }
+
+func setWindowFloating(window *glfw.Window, floating bool) {
+	f := C.int(0)
+	if floating {
+		f = 1
+	}
+	C.setWindowFloating(unsafe.Pointer(window.GetWin32Window()), f)
+}
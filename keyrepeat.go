@@ -0,0 +1,99 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	keyRepeatDelay    = int32(30)
+	keyRepeatInterval = int32(3)
+)
+
+// SetKeyRepeat sets the delay and the interval of key repeating for IsKeyRepeated.
+//
+// delay is the number of frames until a held key starts repeating,
+// and interval is the number of frames between repeats.
+// The default values are 30 (0.5 second) and 3 (0.05 second).
+//
+// If delay or interval is not positive, SetKeyRepeat panics.
+//
+// This function is concurrent-safe.
+func SetKeyRepeat(delay, interval int) {
+	if delay <= 0 {
+		panic("ebiten: delay must be positive")
+	}
+	if interval <= 0 {
+		panic("ebiten: interval must be positive")
+	}
+	atomic.StoreInt32(&keyRepeatDelay, int32(delay))
+	atomic.StoreInt32(&keyRepeatInterval, int32(interval))
+}
+
+// IsKeyRepeated returns a boolean indicating whether key is just pressed or is repeated by holding.
+//
+// IsKeyRepeated returns true at the frame when key is pressed,
+// and then returns true periodically while key is held, like the OS's key repeat.
+// This is useful e.g. for menu navigation.
+// The delay and the interval of repeating can be changed by SetKeyRepeat.
+//
+// This function is concurrent-safe.
+func IsKeyRepeated(key Key) bool {
+	d := theKeyDurations.duration(key)
+	if d == 0 {
+		return false
+	}
+	if d == 1 {
+		return true
+	}
+	delay := int(atomic.LoadInt32(&keyRepeatDelay))
+	interval := int(atomic.LoadInt32(&keyRepeatInterval))
+	if d <= delay {
+		return false
+	}
+	return (d-delay-1)%interval == 0
+}
+
+// keyDurations records how many frames each key has been pressed for.
+type keyDurations struct {
+	durations [KeyMax + 1]int
+	m         sync.RWMutex
+}
+
+var theKeyDurations keyDurations
+
+// update updates the durations. This must be called once every frame.
+func (k *keyDurations) update() {
+	k.m.Lock()
+	defer k.m.Unlock()
+	for key := Key(0); key <= KeyMax; key++ {
+		if IsKeyPressed(key) {
+			k.durations[key]++
+			continue
+		}
+		k.durations[key] = 0
+	}
+}
+
+func (k *keyDurations) duration(key Key) int {
+	if key < 0 || KeyMax < key {
+		return 0
+	}
+	k.m.RLock()
+	defer k.m.RUnlock()
+	return k.durations[key]
+}
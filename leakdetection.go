@@ -0,0 +1,118 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var imageLeakDetection = int32(0)
+
+// SetImageLeakDetection enables or disables the detection of leaked images for debugging.
+//
+// When the leak detection is enabled, the stack traces where images are created by
+// NewImage, NewImageFromImage and the like are recorded.
+// The images that are alive but not disposed yet can be queried by UndisposedImageStacks, and
+// they are reported to the standard logger when Run finishes.
+// Images created while the leak detection is disabled are not tracked.
+//
+// Recording stack traces is slow and this should be used only for debugging.
+// When the leak detection is disabled (default), there is no additional cost.
+//
+// This function is concurrent-safe.
+func SetImageLeakDetection(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&imageLeakDetection, v)
+}
+
+// IsImageLeakDetectionEnabled returns a boolean value indicating whether the leak detection of images is enabled.
+//
+// This function is concurrent-safe.
+func IsImageLeakDetectionEnabled() bool {
+	return atomic.LoadInt32(&imageLeakDetection) != 0
+}
+
+type imageTracker struct {
+	stacks map[int]string
+	nextID int
+	m      sync.Mutex
+}
+
+var theImageTracker = &imageTracker{
+	stacks: map[int]string{},
+}
+
+// trackImage records the stack trace where the image i is created if the leak detection is enabled.
+func trackImage(i *Image) {
+	if !IsImageLeakDetectionEnabled() {
+		return
+	}
+	t := theImageTracker
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.nextID++
+	i.trackingID = t.nextID
+	t.stacks[i.trackingID] = string(debug.Stack())
+}
+
+// untrackImage removes the record of the image i.
+func untrackImage(i *Image) {
+	if i.trackingID == 0 {
+		return
+	}
+	t := theImageTracker
+	t.m.Lock()
+	defer t.m.Unlock()
+	delete(t.stacks, i.trackingID)
+	i.trackingID = 0
+}
+
+// UndisposedImageStacks returns the stack traces where the tracked images that are not disposed yet are created.
+//
+// Images collected by GC are regarded as disposed.
+// The stack traces are in the order of creation.
+//
+// See also SetImageLeakDetection.
+//
+// This function is concurrent-safe.
+func UndisposedImageStacks() []string {
+	t := theImageTracker
+	t.m.Lock()
+	defer t.m.Unlock()
+	ids := make([]int, 0, len(t.stacks))
+	for id := range t.stacks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	stacks := make([]string, 0, len(ids))
+	for _, id := range ids {
+		stacks = append(stacks, t.stacks[id])
+	}
+	return stacks
+}
+
+// reportUndisposedImages logs the undisposed images.
+func reportUndisposedImages() {
+	for _, s := range UndisposedImageStacks() {
+		log.Printf("ebiten: image created at the following stack is not disposed:\n%s", s)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func countStacksWith(substr string) int {
+	n := 0
+	for _, s := range UndisposedImageStacks() {
+		if strings.Contains(s, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestImageLeakDetection(t *testing.T) {
+	SetImageLeakDetection(true)
+	defer SetImageLeakDetection(false)
+
+	img, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := countStacksWith("TestImageLeakDetection"), 1; got != want {
+		t.Errorf("undisposed image stacks: got %d, want: %d", got, want)
+	}
+
+	if err := img.Dispose(); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := countStacksWith("TestImageLeakDetection"), 0; got != want {
+		t.Errorf("undisposed image stacks after Dispose: got %d, want: %d", got, want)
+	}
+	runtime.KeepAlive(img)
+}
+
+func TestImageLeakDetectionDisabled(t *testing.T) {
+	SetImageLeakDetection(false)
+
+	img, err := NewImage(16, 16, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	defer img.Dispose()
+	if got, want := countStacksWith("TestImageLeakDetectionDisabled"), 0; got != want {
+		t.Errorf("undisposed image stacks: got %d, want: %d", got, want)
+	}
+}
@@ -26,5 +26,8 @@ func update() error {
 	return nil
 }
 
+func setFramebuffer(fbo int) {
+}
+
 func start(f func(*ebiten.Image) error, width, height int, scale float64, title string) {
 }
@@ -32,6 +32,10 @@ func update() error {
 	return ui.Render(chError)
 }
 
+func setFramebuffer(fbo int) {
+	ui.SetScreenFramebuffer(uint32(fbo))
+}
+
 func start(f func(*ebiten.Image) error, width, height int, scale float64, title string) {
 	chError = ebiten.RunWithoutMainLoop(f, width, height, scale, title)
 }
@@ -30,6 +30,31 @@ func Start(f func(*ebiten.Image) error, width, height int, scale float64, title
 	return nil
 }
 
+// SetFramebuffer specifies the OpenGL framebuffer object that the game is rendered into.
+//
+// By default, the game is rendered into the framebuffer that is bound when the game's
+// graphics are initialized, which is usually the view's default framebuffer.
+// SetFramebuffer lets the host application embed the game into its own rendering,
+// e.g. by rendering into an FBO with a texture attachment and compositing the texture.
+//
+// The framebuffer must be created in the same GL context that Update is called with,
+// or in a context sharing objects with it. Note that framebuffer objects themselves
+// are never shared between GL contexts even when the contexts share textures:
+// create the FBO on the rendering thread that calls Update.
+// The framebuffer must be complete and its size must match the size passed to Start
+// multiplied by the scale and the device scale.
+//
+// SetFramebuffer must be called before Start. If fbo is negative, SetFramebuffer panics.
+//
+// SetFramebuffer is the mobile counterpart of ebiten.SetScreenFramebuffer.
+// On desktops, where this package does nothing, use ebiten.SetScreenFramebuffer with ebiten.Run instead.
+func SetFramebuffer(fbo int) {
+	if fbo < 0 {
+		panic("mobile: fbo must be non-negative")
+	}
+	setFramebuffer(fbo)
+}
+
 // Update updates and renders the game.
 //
 // This should be called on every frame.
@@ -0,0 +1,73 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+var theProfileCallback atomic.Value
+
+type profileCallback func(update, draw, present time.Duration)
+
+// SetProfileCallback sets the function called every frame with the durations of the phases of the previous frame.
+//
+// update is the total duration of the calls of the function passed to Run.
+// draw is the duration of rendering the screen image to the window, including flushing the drawing commands to GPU.
+// present is the duration from the end of drawing to the start of the next frame,
+// including swapping buffers and waiting for the vertical sync.
+//
+// The callback is called on the same goroutine as the function passed to Run, and
+// is not called while the game loop is suspended (e.g. when the screen is not shown).
+// The callback should be light since its duration is included in the next frame.
+//
+// If f is nil, the callback is removed.
+//
+// This function is concurrent-safe.
+func SetProfileCallback(f func(update, draw, present time.Duration)) {
+	theProfileCallback.Store(profileCallback(f))
+}
+
+func callProfileCallback(update, draw, present time.Duration) {
+	f, ok := theProfileCallback.Load().(profileCallback)
+	if !ok || f == nil {
+		return
+	}
+	f(update, draw, present)
+}
+
+// SetPresentCallback sets the function called every time a frame is presented,
+// with the time right after the presentation.
+//
+// On desktops, t is the time right after swapping buffers, which includes waiting for the vertical sync.
+// On browsers and mobiles, where the presentation is done by the platform, t is an approximation:
+// on browsers, t is the time when the next animation frame starts, and
+// on mobiles, t is the time when the rendering of the frame is handed to the platform.
+//
+// The intervals of t are useful to measure the actual frame cadence and to detect dropped frames.
+// Combined with SetProfileCallback, this helps to build frame-time graphs.
+//
+// The callback might be called on a different goroutine from the function passed to Run.
+// The callback should be light since it blocks the game loop.
+//
+// If f is nil, the callback is removed.
+//
+// This function is concurrent-safe.
+func SetPresentCallback(f func(t time.Time)) {
+	ui.SetPresentCallback(f)
+}
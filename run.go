@@ -15,8 +15,13 @@
 package ebiten
 
 import (
+	"fmt"
+	"image/color"
+	"math"
 	"sync/atomic"
+	"time"
 
+	"github.com/hajimehoshi/ebiten/internal/graphics"
 	"github.com/hajimehoshi/ebiten/internal/loop"
 	"github.com/hajimehoshi/ebiten/internal/ui"
 )
@@ -36,6 +41,36 @@ func CurrentFPS() float64 {
 	return loop.CurrentFPS()
 }
 
+// Now returns the elapsed time since Run started.
+//
+// The time is monotonic: it is never affected by changes of the system clock, and always increases
+// regardless of the frame rate, skipped frames or whether the game is running slowly.
+// This is useful for timers in real time like cooldowns.
+// Note that Now is not affected by any game-specific time scaling; scale the value by yourself if needed.
+//
+// Before Run is called, Now returns 0.
+//
+// This function is concurrent-safe.
+func Now() time.Duration {
+	return loop.Now()
+}
+
+// DrawCallCount returns the number of draw calls issued in the previous frame.
+//
+// Draw calls are counted after batching, so this value is useful to check that
+// consecutive DrawImage calls are batched as expected.
+//
+// This function is concurrent-safe.
+func DrawCallCount() int {
+	return int(atomic.LoadInt64(&drawCallCount))
+}
+
+var drawCallCount = int64(0)
+
+func setDrawCallCount(n int) {
+	atomic.StoreInt64(&drawCallCount, int64(n))
+}
+
 var (
 	isRunningSlowly = int32(0)
 )
@@ -57,6 +92,91 @@ func IsRunningSlowly() bool {
 	return atomic.LoadInt32(&isRunningSlowly) != 0
 }
 
+// SetMaxSkippedFrames sets the maximum number of frames that can be skipped to catch up
+// when the game runs slowly.
+//
+// When an update or a frame takes longer than 1/60 seconds, the game loop calls the function passed to Run
+// multiple times in the next frame to catch up with the real time. Only the last call renders the screen,
+// and IsRunningSlowly returns true in the other calls (skipped frames).
+// The number of the calls in one frame is at most n + 1. When more time has passed,
+// the remaining time is dropped and the game's logical time falls behind the real time.
+// This prevents the 'spiral of death', where slow updates cause more updates to catch up.
+//
+// If n is 0, the function is called exactly once per frame and frames are never skipped:
+// the game slows down instead when the game runs slowly.
+//
+// Independently, when more than 10 frames have passed (e.g. when the window is hidden),
+// the game loop doesn't catch up and just restarts from the current time.
+//
+// The default value is 9.
+//
+// If n is negative, SetMaxSkippedFrames panics.
+//
+// This function is concurrent-safe.
+func SetMaxSkippedFrames(n int) {
+	if n < 0 {
+		panic("ebiten: n must not be negative")
+	}
+	loop.SetMaxSkippedFrames(n)
+}
+
+// MaxSkippedFrames returns the maximum number of frames that can be skipped to catch up.
+//
+// This function is concurrent-safe.
+func MaxSkippedFrames() int {
+	return loop.MaxSkippedFrames()
+}
+
+var isFirstUpdate = int32(0)
+
+func setFirstUpdate(first bool) {
+	v := int32(0)
+	if first {
+		v = 1
+	}
+	atomic.StoreInt32(&isFirstUpdate, v)
+}
+
+// IsFirstUpdate returns true if the current call of the function passed to Run is the first one.
+//
+// The graphics context is available when the function passed to Run is called,
+// while it is not before Run is called.
+// IsFirstUpdate is useful to do one-time initialization that requires the graphics context,
+// like creating render targets.
+//
+// This function is concurrent-safe.
+func IsFirstUpdate() bool {
+	return atomic.LoadInt32(&isFirstUpdate) != 0
+}
+
+var screenClearedEveryFrame = int32(1)
+
+// SetScreenClearedEveryFrame enables or disables the clearing of the screen at the beginning of each frame.
+// The default value is true and the screen is cleared each frame by default.
+//
+// When disabled, the screen image passed to the game function keeps the contents drawn at the previous frame.
+// This is useful for games that redraw the whole screen anyway, or that intentionally accumulate drawings.
+//
+// Note that the screen image is an offscreen image and is different from the double-buffered framebuffer.
+// Then, the persisted contents are always the ones from the previous frame, not the ones from two frames ago.
+// The persisted contents are not restored when the graphics context is lost (e.g. on browsers or mobiles).
+//
+// This function is concurrent-safe.
+func SetScreenClearedEveryFrame(cleared bool) {
+	v := int32(0)
+	if cleared {
+		v = 1
+	}
+	atomic.StoreInt32(&screenClearedEveryFrame, v)
+}
+
+// IsScreenClearedEveryFrame returns true if the screen is cleared at the beginning of each frame.
+//
+// This function is concurrent-safe.
+func IsScreenClearedEveryFrame() bool {
+	return atomic.LoadInt32(&screenClearedEveryFrame) != 0
+}
+
 var theGraphicsContext atomic.Value
 
 // Run runs the game.
@@ -79,7 +199,9 @@ func Run(f func(*Image) error, width, height int, scale float64, title string) e
 	go func() {
 		g := newGraphicsContext(f)
 		theGraphicsContext.Store(g)
-		if err := loop.Run(g, width, height, scale, title, FPS); err != nil {
+		err := loop.Run(g, width, height, scale, title, FPS)
+		reportUndisposedImages()
+		if err != nil {
 			ch <- err
 		}
 		close(ch)
@@ -103,7 +225,9 @@ func RunWithoutMainLoop(f func(*Image) error, width, height int, scale float64,
 	go func() {
 		g := newGraphicsContext(f)
 		theGraphicsContext.Store(g)
-		if err := loop.Run(g, width, height, scale, title, FPS); err != nil {
+		err := loop.Run(g, width, height, scale, title, FPS)
+		reportUndisposedImages()
+		if err != nil {
 			ch <- err
 		}
 		close(ch)
@@ -111,6 +235,48 @@ func RunWithoutMainLoop(f func(*Image) error, width, height int, scale float64,
 	return ch
 }
 
+// IsRunning returns a boolean indicating whether the game is running,
+// i.e., Run or RunWithoutMainLoop is called and the game has not finished yet.
+//
+// Graphics resources on GPU are not available before the game starts.
+// Libraries that can be used both before and after Run can check this
+// to defer work that requires the graphics context.
+//
+// This function is concurrent-safe.
+func IsRunning() bool {
+	return loop.IsRunning()
+}
+
+// RunOnGraphicsThread runs f on the thread where the graphics (OpenGL) context is current,
+// and returns after f finishes.
+//
+// Some platforms require all OpenGL calls to be done on the thread that created the context.
+// RunOnGraphicsThread is useful to make OpenGL calls e.g. from other libraries using cgo.
+// The drawing commands enqueued by Ebiten before RunOnGraphicsThread are flushed before f is called.
+// f must restore the OpenGL states (e.g. the bound framebuffer, texture and program) that f changes,
+// since Ebiten caches them.
+//
+// RunOnGraphicsThread must be called after the game starts, typically from the function passed to Run.
+// Otherwise, RunOnGraphicsThread panics.
+//
+// Calling RunOnGraphicsThread from f itself causes a deadlock,
+// since the graphics thread is waiting for f to finish.
+// On mobiles, RunOnGraphicsThread called outside of the function passed to Run
+// blocks until the next frame is rendered.
+func RunOnGraphicsThread(f func()) error {
+	c := ui.GLContext()
+	if c == nil {
+		panic("ebiten: RunOnGraphicsThread must be called after the game starts")
+	}
+	if err := graphics.FlushCommands(c); err != nil {
+		return err
+	}
+	return c.RunOnContextThread(func() error {
+		f()
+		return nil
+	})
+}
+
 // SetScreenSize changes the (logical) size of the screen.
 // This doesn't affect the current scale of the screen.
 //
@@ -134,6 +300,34 @@ func SetScreenScale(scale float64) {
 	ui.SetScreenScale(scale)
 }
 
+// SetPixelAspectRatio sets the aspect ratio (width / height) of a pixel of the screen.
+//
+// The screen is stretched horizontally by ratio on the window, and this is independent from the screen scale.
+// For example, a 256x224 screen with ratio 8/7 is shown in 4:3.
+// This is different from letterboxing, which preserves the aspect ratio of the screen.
+// This affects only how the screen is rendered, and the logical screen size and
+// the cursor position in the logical screen are not changed.
+//
+// The default value is 1. SetPixelAspectRatio can be called before Run.
+// On mobiles, SetPixelAspectRatio does nothing so far.
+//
+// If ratio is not positive, SetPixelAspectRatio panics.
+//
+// This function is concurrent-safe.
+func SetPixelAspectRatio(ratio float64) {
+	if !(0 < ratio) {
+		panic("ebiten: ratio must be positive")
+	}
+	ui.SetPixelAspectRatio(ratio)
+}
+
+// PixelAspectRatio returns the current pixel aspect ratio.
+//
+// This function is concurrent-safe.
+func PixelAspectRatio() float64 {
+	return ui.PixelAspectRatio()
+}
+
 // ScreenScale returns the current screen scale.
 //
 // If Run is not called, this returns 0.
@@ -143,9 +337,352 @@ func ScreenScale() float64 {
 	return ui.ScreenScale()
 }
 
+// FramebufferSize returns the size of the framebuffer in actual pixels.
+//
+// The framebuffer size is the screen size multiplied by the screen scale and the device scale (e.g. high DPI).
+// Note that the game screen is rendered with a fitting scale to the framebuffer.
+//
+// If Run is not called, this returns (0, 0) on desktops.
+//
+// This function is concurrent-safe.
+func FramebufferSize() (width, height int) {
+	return ui.FramebufferSize()
+}
+
+// ScreenSizeInFullscreen returns the size of the monitor (desktops) or the browser window (browsers)
+// in device-independent pixels.
+//
+// This is useful e.g. to determine the screen size or the screen scale before Run.
+// On desktops, ScreenSizeInFullscreen must be called on the main thread before Run.
+// On mobiles, this is not implemented and always returns (0, 0).
+//
+// This function is concurrent-safe after Run is called.
+func ScreenSizeInFullscreen() (width, height int) {
+	return ui.ScreenSizeInFullscreen()
+}
+
+// DisplayRefreshRate returns the refresh rate [Hz] of the display where the window is.
+//
+// This is useful e.g. to warn about a mismatch between the game's FPS and the display,
+// or to choose a frame rate matching the display.
+//
+// On desktops, DisplayRefreshRate must be called on the main thread before Run.
+// On browsers, the refresh rate is not available directly, and DisplayRefreshRate returns
+// an estimation from the intervals of animation frames after Run is called.
+// On mobiles, this is not implemented.
+// DisplayRefreshRate returns 0 when the refresh rate is unknown.
+//
+// This function is concurrent-safe after Run is called.
+func DisplayRefreshRate() float64 {
+	return ui.DisplayRefreshRate()
+}
+
+// IsVsyncEffective returns a boolean value indicating whether presenting frames is actually synced
+// with the display's vertical refresh.
+//
+// Ebiten always requests vsync, but some graphics drivers override it (e.g. by the driver's settings).
+// On desktops, the backend can't report the effective state, and IsVsyncEffective estimates it from
+// the measured frame cadence: if frames are presented more often than DisplayRefreshRate (or 60Hz
+// when the refresh rate is unknown), vsync is regarded as not effective.
+// The estimation needs some frames after Run starts, and IsVsyncEffective returns true until then.
+// On browsers and mobiles, frames are always synced with the display, and IsVsyncEffective returns true.
+//
+// This function is concurrent-safe after Run is called.
+func IsVsyncEffective() bool {
+	return ui.IsVsyncEffective()
+}
+
+// SetBufferCount sets the number of buffers used to present frames: 2 for double buffering or
+// 3 for triple buffering.
+//
+// OpenGL can't choose the number of buffers directly, and n is a request rather than the actual number
+// of the buffers.
+// With 3, Ebiten doesn't limit the frames queued by the graphics driver, and the actual number of
+// the buffers is driver-controlled. This keeps the frame rate smooth when rendering a frame sometimes
+// takes longer, but can add latency between input and the display.
+// With 2, Ebiten calls glFinish after swapping buffers on desktops, which blocks until the GPU finishes
+// the frame so that the driver doesn't queue the next frame ahead. This reduces the latency at the cost
+// of smoothness.
+//
+// The combinations with vsync (see IsVsyncEffective) are:
+//
+//     vsync on,  3: smooth, with the latency of the frames the driver queues
+//     vsync on,  2: less latency, but a slow frame waits for the next refresh
+//     vsync off, 3: driver-controlled; tearing may happen
+//     vsync off, 2: the lowest latency; tearing may happen
+//
+// The default value is 3, i.e., driver-controlled.
+//
+// On browsers and mobiles, the system controls presenting and SetBufferCount does nothing.
+//
+// If n is neither 2 nor 3, SetBufferCount panics.
+//
+// This function is concurrent-safe.
+func SetBufferCount(n int) {
+	if n != 2 && n != 3 {
+		panic("ebiten: n must be 2 or 3")
+	}
+	ui.SetBufferCount(n)
+}
+
+// BufferCount returns the number of buffers requested by SetBufferCount.
+//
+// BufferCount doesn't query the graphics driver: the returned value is not the actual number of the buffers.
+// 3 means that the number is driver-controlled.
+//
+// This function is concurrent-safe.
+func BufferCount() int {
+	return ui.BufferCount()
+}
+
+// ClipboardText returns the text in the clipboard.
+//
+// This is useful e.g. to paste text into a text field by Ctrl+V.
+// When the clipboard is empty or doesn't contain text, ClipboardText returns an empty string without error.
+//
+// On desktops, ClipboardText must be called on the main thread before Run.
+// On browsers and mobiles, reading the clipboard is not supported and ClipboardText returns an error.
+//
+// This function is concurrent-safe after Run is called.
+func ClipboardText() (string, error) {
+	return ui.ClipboardText()
+}
+
+// SetClipboardText sets the text in the clipboard.
+//
+// On desktops, SetClipboardText must be called on the main thread before Run.
+// On browsers, SetClipboardText uses the asynchronous Clipboard API if available,
+// and might fail silently e.g. without a user's gesture.
+// On mobiles, SetClipboardText does nothing.
+//
+// This function is concurrent-safe after Run is called.
+func SetClipboardText(text string) {
+	ui.SetClipboardText(text)
+}
+
 // SetCursorVisibility changes the state of cursor visiblity.
 //
 // This function is concurrent-safe.
 func SetCursorVisibility(visible bool) {
 	ui.SetCursorVisibility(visible)
 }
+
+// SetScreenFramebuffer specifies the OpenGL framebuffer object that the game screen is rendered into
+// instead of the window's default framebuffer.
+//
+// This is for embedding the game into a host application like an editor or a VR overlay:
+// the host renders the game into an FBO with a texture attachment and composites the texture
+// into its own rendering. The window is still created and its buffers are still swapped.
+//
+// The framebuffer must be complete and its size must match the screen size multiplied by
+// the scale and the device scale.
+//
+// Framebuffer objects are never shared between GL contexts, even when the contexts share textures.
+// Then, fbo must be a framebuffer created in Ebiten's GL context:
+//
+//   - On desktops, Ebiten's GL context is created at the program initialization and is current
+//     on the main thread. Create the FBO on the main thread before calling Run.
+//     To share the attached texture with the host, the host's GL context must share objects with Ebiten's.
+//   - On mobiles, Ebiten uses the GL context of the view. Create the FBO on the rendering thread
+//     (see also mobile.SetFramebuffer).
+//
+// SetScreenFramebuffer must be called before Run. If fbo is negative, SetScreenFramebuffer panics.
+//
+// On browsers, SetScreenFramebuffer does nothing.
+func SetScreenFramebuffer(fbo int) {
+	if fbo < 0 {
+		panic("ebiten: fbo must be non-negative")
+	}
+	ui.SetScreenFramebuffer(uint32(fbo))
+}
+
+// SetCursorShape changes the shape of the mouse cursor to one of the system's standard cursors.
+//
+// This is useful e.g. to show the I-beam cursor on a text field or the hand cursor on a button.
+// The shape is kept while the cursor is hidden, and applied when the cursor is shown again.
+// The default shape is CursorShapeDefault.
+//
+// If shape is not a valid value, SetCursorShape panics.
+//
+// On mobiles, SetCursorShape does nothing.
+//
+// This function is concurrent-safe.
+func SetCursorShape(shape CursorShape) {
+	switch shape {
+	case CursorShapeDefault, CursorShapeText, CursorShapeCrosshair, CursorShapePointer, CursorShapeEWResize, CursorShapeNSResize:
+	default:
+		panic(fmt.Sprintf("ebiten: invalid cursor shape: %d", shape))
+	}
+	ui.SetCursorShape(ui.CursorShape(shape))
+}
+
+// SetCursorMode changes the mode of the mouse cursor.
+//
+// In CursorModeCaptured, the cursor is hidden and locked to the window,
+// and the cursor movement can be got by CursorDelta.
+// This is useful e.g. for a first-person camera.
+//
+// On browsers, capturing the cursor requires a user gesture, and
+// the cursor is actually captured when the user clicks the canvas.
+// On mobiles, SetCursorMode does nothing.
+//
+// If mode is not a valid value, SetCursorMode panics.
+//
+// This function is concurrent-safe.
+func SetCursorMode(mode CursorMode) {
+	switch mode {
+	case CursorModeVisible, CursorModeHidden, CursorModeCaptured:
+	default:
+		panic(fmt.Sprintf("ebiten: invalid cursor mode: %d", mode))
+	}
+	ui.SetCursorMode(ui.CursorMode(mode))
+}
+
+// SetFocusCallback sets the function called when the window gains or loses focus.
+//
+// This is useful e.g. to pause the game, to mute the audio or to dim the screen immediately when the window loses focus.
+// The callback is called only when the focus state actually changes. The window is treated as focused at start.
+//
+// On desktops, the callback is called on the same goroutine as the function passed to Run.
+// On browsers, the callback is called in the focus and blur event handlers of the window, and
+// the callback must not block.
+// On mobiles, the callback is never called.
+//
+// If f is nil, the callback is removed.
+//
+// This function is concurrent-safe.
+func SetFocusCallback(f func(focused bool)) {
+	ui.SetFocusCallback(f)
+}
+
+var theGraphicsReadyCallback atomic.Value
+
+type graphicsReadyCallback func()
+
+// SetGraphicsReadyCallback sets the function called once when the graphics context becomes ready.
+//
+// The callback is called after the graphics context is initialized and before the first call of
+// the function passed to Run, on the same goroutine as the function.
+// In the callback, graphics-dependent functions like CurrentGraphicsInfo, NewExactSizeImage and
+// NewFloatImage are available. This is useful for libraries to initialize such resources at a deterministic point.
+//
+// The callback is called only once per Run. Note that it is not called again when the graphics context
+// is lost and restored; Ebiten restores images automatically in this case.
+//
+// SetGraphicsReadyCallback must be called before Run to be effective.
+// If f is nil, the callback is removed.
+//
+// This function is concurrent-safe.
+func SetGraphicsReadyCallback(f func()) {
+	theGraphicsReadyCallback.Store(graphicsReadyCallback(f))
+}
+
+func callGraphicsReadyCallback() {
+	f, ok := theGraphicsReadyCallback.Load().(graphicsReadyCallback)
+	if !ok || f == nil {
+		return
+	}
+	f()
+}
+
+// IsFocused returns true if the window is focused.
+//
+// The focus state is updated at every frame. The window is treated as focused at start.
+// On mobiles, IsFocused always returns true.
+//
+// This function is concurrent-safe.
+func IsFocused() bool {
+	return ui.IsFocused()
+}
+
+var pauseAudioOnBlur = int32(1)
+
+// SetPauseAudioOnBlur sets whether the audio is paused automatically while the window is not focused.
+//
+// When this is true, audio.Context pauses all the playing players by PauseAll when the window loses focus,
+// and resumes them by ResumeAll when the window regains focus. This happens as soon as the focus changes,
+// even while the game's update function is not called.
+// On mobiles, the focus is not reported and the audio is not paused automatically.
+//
+// The default value is true.
+//
+// This function is concurrent-safe.
+func SetPauseAudioOnBlur(pause bool) {
+	v := int32(0)
+	if pause {
+		v = 1
+	}
+	atomic.StoreInt32(&pauseAudioOnBlur, v)
+}
+
+// IsPauseAudioOnBlur returns true if the audio is paused automatically while the window is not focused.
+//
+// This function is concurrent-safe.
+func IsPauseAudioOnBlur() bool {
+	return atomic.LoadInt32(&pauseAudioOnBlur) != 0
+}
+
+// SetWindowFloating sets whether the window is kept above other windows (always-on-top).
+//
+// This is useful e.g. for overlays and companion tools.
+// The default value is false.
+//
+// SetWindowFloating is supported on Windows and macOS.
+// On the other platforms including Linux, browsers and mobiles, SetWindowFloating does nothing.
+//
+// This function is concurrent-safe.
+func SetWindowFloating(floating bool) {
+	ui.SetWindowFloating(floating)
+}
+
+// SetGamma sets the gamma value of the output.
+//
+// The gamma correction is applied as a final full-screen pass when the game screen is rendered to the window,
+// and doesn't affect the pixels of any images including the screen image passed to the game function.
+// A gamma value more than 1 brightens the output and a value less than 1 darkens the output.
+// This is useful e.g. for a brightness setting.
+// The default value is 1, which means no correction.
+//
+// If gamma is not a positive finite value, SetGamma panics.
+//
+// This function is concurrent-safe.
+func SetGamma(gamma float64) {
+	// The condition must be true when gamma is NaN.
+	if !(0 < gamma && !math.IsInf(gamma, 1)) {
+		panic("ebiten: gamma must be a positive finite value")
+	}
+	graphics.SetScreenGamma(gamma)
+}
+
+// Gamma returns the current gamma value of the output.
+//
+// This function is concurrent-safe.
+func Gamma() float64 {
+	return graphics.ScreenGamma()
+}
+
+var theBorderColor atomic.Value
+
+// SetBorderColor sets the color of the area outside of the game screen (the letterbox bars).
+//
+// The alpha value of clr is ignored, and the border is drawn opaque with the color components of clr.
+// The default color is black.
+// On browsers, this also changes the background color of the page around the canvas.
+//
+// This function is concurrent-safe.
+func SetBorderColor(clr color.Color) {
+	// The border is always opaque. Use the non-premultiplied values so that a translucent color
+	// doesn't get darker.
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	rgba := color.RGBA{c.R, c.G, c.B, 0xff}
+	theBorderColor.Store(rgba)
+	ui.SetBorderColor(rgba.R, rgba.G, rgba.B)
+}
+
+func borderColor() color.RGBA {
+	clr, ok := theBorderColor.Load().(color.RGBA)
+	if !ok {
+		return color.RGBA{0, 0, 0, 0xff}
+	}
+	return clr
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSetBorderColor(t *testing.T) {
+	defer SetBorderColor(color.Black)
+
+	cases := []struct {
+		In   color.Color
+		Want color.RGBA
+	}{
+		{color.RGBA{0x80, 0x40, 0x20, 0xff}, color.RGBA{0x80, 0x40, 0x20, 0xff}},
+		// The alpha value is ignored without darkening the color.
+		{color.NRGBA{0xff, 0, 0, 0x80}, color.RGBA{0xff, 0, 0, 0xff}},
+		{color.RGBA{0x40, 0x40, 0, 0x80}, color.RGBA{0x7f, 0x7f, 0, 0xff}},
+		{color.Transparent, color.RGBA{0, 0, 0, 0xff}},
+	}
+	for _, c := range cases {
+		SetBorderColor(c.In)
+		if got := borderColor(); got != c.Want {
+			t.Errorf("SetBorderColor(%v): got %v, want: %v", c.In, got, c.Want)
+		}
+	}
+}
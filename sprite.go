@@ -0,0 +1,244 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sort"
+)
+
+// Sprite is a retained drawing object used with Scene.
+//
+// A Sprite holds an image, a transform, a color matrix and a z value.
+// Changes via the setter methods are tracked by the scene the sprite belongs to.
+//
+// The zero value is not usable. Use NewSprite instead.
+type Sprite struct {
+	image   *Image
+	geoM    GeoM
+	colorM  ColorM
+	z       int
+	visible bool
+
+	scene *Scene
+	order int
+}
+
+// NewSprite returns a new visible sprite with the given image.
+func NewSprite(image *Image) *Sprite {
+	return &Sprite{
+		image:   image,
+		visible: true,
+	}
+}
+
+func (s *Sprite) markDirty() {
+	if s.scene != nil {
+		s.scene.dirty = true
+	}
+}
+
+// Image returns the sprite's image.
+func (s *Sprite) Image() *Image {
+	return s.image
+}
+
+// SetImage sets the sprite's image.
+func (s *Sprite) SetImage(image *Image) {
+	if s.image == image {
+		return
+	}
+	s.image = image
+	s.markDirty()
+}
+
+// GeoM returns the sprite's geometry matrix.
+func (s *Sprite) GeoM() GeoM {
+	return s.geoM
+}
+
+// SetGeoM sets the sprite's geometry matrix.
+func (s *Sprite) SetGeoM(geoM GeoM) {
+	s.geoM = geoM
+	s.markDirty()
+}
+
+// ColorM returns the sprite's color matrix.
+func (s *Sprite) ColorM() ColorM {
+	return s.colorM
+}
+
+// SetColorM sets the sprite's color matrix.
+func (s *Sprite) SetColorM(colorM ColorM) {
+	s.colorM = colorM
+	s.markDirty()
+}
+
+// Z returns the sprite's z value.
+func (s *Sprite) Z() int {
+	return s.z
+}
+
+// SetZ sets the sprite's z value.
+//
+// A sprite with a greater z value is drawn over sprites with less z values.
+// Sprites with the same z value are drawn in the order added to the scene.
+func (s *Sprite) SetZ(z int) {
+	if s.z == z {
+		return
+	}
+	s.z = z
+	if s.scene != nil {
+		s.scene.sorted = false
+	}
+	s.markDirty()
+}
+
+// IsVisible returns a boolean value indicating whether the sprite is drawn.
+func (s *Sprite) IsVisible() bool {
+	return s.visible
+}
+
+// SetVisible sets the sprite's visibility.
+func (s *Sprite) SetVisible(visible bool) {
+	if s.visible == visible {
+		return
+	}
+	s.visible = visible
+	s.markDirty()
+}
+
+// Scene is a set of sprites drawn in the order of their z values.
+//
+// Scene is a convenience layered on DrawImage. Scene keeps the rendering result in an offscreen image,
+// and skips rendering the sprites again when nothing is changed since the last Draw.
+// Note that changes of the sprites' images' pixels are not tracked: call Invalidate in this case.
+//
+// Scene is not concurrent-safe.
+type Scene struct {
+	sprites   []*Sprite
+	sorted    bool
+	dirty     bool
+	cache     *Image
+	nextOrder int
+}
+
+// NewScene returns a new empty scene.
+func NewScene() *Scene {
+	return &Scene{
+		sorted: true,
+		dirty:  true,
+	}
+}
+
+// Add adds the sprite to the scene.
+//
+// If the sprite already belongs to a scene, Add panics.
+func (s *Scene) Add(sprite *Sprite) {
+	if sprite.scene != nil {
+		panic("ebiten: the sprite already belongs to a scene")
+	}
+	sprite.scene = s
+	sprite.order = s.nextOrder
+	s.nextOrder++
+	s.sprites = append(s.sprites, sprite)
+	s.sorted = false
+	s.dirty = true
+}
+
+// Remove removes the sprite from the scene.
+//
+// If the sprite doesn't belong to the scene, Remove does nothing.
+func (s *Scene) Remove(sprite *Sprite) {
+	if sprite.scene != s {
+		return
+	}
+	for i, sp := range s.sprites {
+		if sp == sprite {
+			s.sprites = append(s.sprites[:i], s.sprites[i+1:]...)
+			break
+		}
+	}
+	sprite.scene = nil
+	s.dirty = true
+}
+
+// Invalidate marks the scene as changed so that the sprites are rendered again at the next Draw.
+//
+// Invalidate is useful e.g. when the pixels of a sprite's image are changed.
+func (s *Scene) Invalidate() {
+	s.dirty = true
+}
+
+type spritesByZ []*Sprite
+
+func (s spritesByZ) Len() int {
+	return len(s)
+}
+
+func (s spritesByZ) Less(i, j int) bool {
+	if s[i].z != s[j].z {
+		return s[i].z < s[j].z
+	}
+	return s[i].order < s[j].order
+}
+
+func (s spritesByZ) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Draw draws the scene's sprites on dst.
+//
+// Draw returns error when creating the offscreen image fails.
+func (s *Scene) Draw(dst *Image) error {
+	w, h := dst.Size()
+	if s.cache != nil {
+		if cw, ch := s.cache.Size(); cw != w || ch != h {
+			if err := s.cache.Dispose(); err != nil {
+				return err
+			}
+			s.cache = nil
+		}
+	}
+	if s.cache == nil {
+		img, err := NewImage(w, h, FilterNearest)
+		if err != nil {
+			return err
+		}
+		s.cache = img
+		s.dirty = true
+	}
+	if s.dirty {
+		if !s.sorted {
+			sort.Sort(spritesByZ(s.sprites))
+			s.sorted = true
+		}
+		if err := s.cache.Clear(); err != nil {
+			return err
+		}
+		op := &DrawImageOptions{}
+		for _, sp := range s.sprites {
+			if !sp.visible || sp.image == nil {
+				continue
+			}
+			op.GeoM = sp.geoM
+			op.ColorM = sp.colorM
+			if err := s.cache.DrawImage(sp.image, op); err != nil {
+				return err
+			}
+		}
+		s.dirty = false
+	}
+	return dst.DrawImage(s.cache, nil)
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image/color"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestSceneZOrder(t *testing.T) {
+	red, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := red.Fill(color.RGBA{0xff, 0, 0, 0xff}); err != nil {
+		t.Fatal(err)
+		return
+	}
+	blue, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := blue.Fill(color.RGBA{0, 0, 0xff, 0xff}); err != nil {
+		t.Fatal(err)
+		return
+	}
+	dst, err := NewImage(4, 4, FilterNearest)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	scene := NewScene()
+	s0 := NewSprite(red)
+	s0.SetZ(1)
+	scene.Add(s0)
+	s1 := NewSprite(blue)
+	scene.Add(s1)
+	if err := scene.Draw(dst); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got %v, want: %v", got, want)
+	}
+
+	s1.SetZ(2)
+	if err := dst.Clear(); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if err := scene.Draw(dst); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{0, 0, 0xff, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/ui"
+)
+
+// A StandardGamepadButton represents a button in the standard gamepad layout.
+//
+// The layout is same as the standard layout of the W3C Gamepad API.
+// For example, StandardGamepadButtonRightBottom is the bottom button of the right cluster
+// (A on Xbox controllers, Cross on PlayStation controllers).
+type StandardGamepadButton int
+
+// StandardGamepadButtons
+const (
+	StandardGamepadButtonRightBottom      = StandardGamepadButton(ui.StandardGamepadButtonRightBottom)
+	StandardGamepadButtonRightRight       = StandardGamepadButton(ui.StandardGamepadButtonRightRight)
+	StandardGamepadButtonRightLeft        = StandardGamepadButton(ui.StandardGamepadButtonRightLeft)
+	StandardGamepadButtonRightTop         = StandardGamepadButton(ui.StandardGamepadButtonRightTop)
+	StandardGamepadButtonFrontTopLeft     = StandardGamepadButton(ui.StandardGamepadButtonFrontTopLeft)
+	StandardGamepadButtonFrontTopRight    = StandardGamepadButton(ui.StandardGamepadButtonFrontTopRight)
+	StandardGamepadButtonFrontBottomLeft  = StandardGamepadButton(ui.StandardGamepadButtonFrontBottomLeft)
+	StandardGamepadButtonFrontBottomRight = StandardGamepadButton(ui.StandardGamepadButtonFrontBottomRight)
+	StandardGamepadButtonCenterLeft       = StandardGamepadButton(ui.StandardGamepadButtonCenterLeft)
+	StandardGamepadButtonCenterRight      = StandardGamepadButton(ui.StandardGamepadButtonCenterRight)
+	StandardGamepadButtonLeftStick        = StandardGamepadButton(ui.StandardGamepadButtonLeftStick)
+	StandardGamepadButtonRightStick       = StandardGamepadButton(ui.StandardGamepadButtonRightStick)
+	StandardGamepadButtonLeftTop          = StandardGamepadButton(ui.StandardGamepadButtonLeftTop)
+	StandardGamepadButtonLeftBottom       = StandardGamepadButton(ui.StandardGamepadButtonLeftBottom)
+	StandardGamepadButtonLeftLeft         = StandardGamepadButton(ui.StandardGamepadButtonLeftLeft)
+	StandardGamepadButtonLeftRight        = StandardGamepadButton(ui.StandardGamepadButtonLeftRight)
+	StandardGamepadButtonCenterCenter     = StandardGamepadButton(ui.StandardGamepadButtonCenterCenter)
+	StandardGamepadButtonMax              = StandardGamepadButtonCenterCenter
+)
+
+// IsStandardGamepadButtonPressed returns a boolean value indicating whether the button in the
+// standard layout is pressed on the gamepad id.
+//
+// On browsers, the standard layout is provided by the browser if the gamepad is known to the browser.
+// On desktops, a small mapping database keyed by the gamepad names (see GamepadName) is used.
+// For unknown gamepads, the raw button index same as button is used as a fallback,
+// i.e. this is equivalent to IsGamepadButtonPressed(id, GamepadButton(button)).
+// If the button is not available as a button on the gamepad (e.g. the triggers reported as axes),
+// IsStandardGamepadButtonPressed returns false.
+//
+// This function is concurrent-safe.
+func IsStandardGamepadButtonPressed(id int, button StandardGamepadButton) bool {
+	return ui.CurrentInput().IsStandardGamepadButtonPressed(id, ui.StandardGamepadButton(button))
+}
+
+// GamepadName returns the name of the gamepad id reported by the driver or the browser.
+//
+// GamepadName returns an empty string when the gamepad is not connected or the name is not available.
+//
+// This function is concurrent-safe.
+func GamepadName(id int) string {
+	return ui.CurrentInput().GamepadName(id)
+}
@@ -15,6 +15,8 @@
 package ebiten
 
 import (
+	"image/color"
+
 	"github.com/hajimehoshi/ebiten/internal/graphics"
 )
 
@@ -25,3 +27,29 @@ import (
 const texelAdjustment = 256
 
 var quadFloat32Num = graphics.QuadVertexSizeInBytes() / 4
+
+// whiteVertexColors represents the vertex colors that don't change the source colors.
+var whiteVertexColors = [4][4]float32{
+	{1, 1, 1, 1},
+	{1, 1, 1, 1},
+	{1, 1, 1, 1},
+	{1, 1, 1, 1},
+}
+
+// vertexColors converts the corner colors into non-premultiplied float values.
+// vertexColors returns nil when all the corner colors are nil, which means white.
+func vertexColors(colors *[4]color.Color) *[4][4]float32 {
+	if colors[0] == nil && colors[1] == nil && colors[2] == nil && colors[3] == nil {
+		return nil
+	}
+	vc := whiteVertexColors
+	for i, clr := range colors {
+		if clr == nil {
+			continue
+		}
+		c := color.NRGBA64Model.Convert(clr).(color.NRGBA64)
+		const max = 0xffff
+		vc[i] = [4]float32{float32(c.R) / max, float32(c.G) / max, float32(c.B) / max, float32(c.A) / max}
+	}
+	return &vc
+}
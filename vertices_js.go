@@ -22,27 +22,23 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/affine"
 )
 
-func vertices(parts ImageParts, width, height int, geo *affine.GeoM) []float32 {
+func vertices(parts ImageParts, textureWidth, textureHeight int, geo *affine.GeoM, colors *[4][4]float32) []float32 {
 	// TODO: This function should be in graphics package?
 	l := parts.Len()
 	vs := js.Global.Get("Float32Array").New(l * quadFloat32Num)
-	g := geo.UnsafeElements()
+	c := colors
+	if c == nil {
+		c = &whiteVertexColors
+	}
+	g := geo.Elements()
 	g0 := g[0]
 	g1 := g[1]
 	g2 := g[3]
 	g3 := g[4]
 	g4 := g[2]
 	g5 := g[5]
-	w := 1
-	h := 1
-	for w < width {
-		w *= 2
-	}
-	for h < height {
-		h *= 2
-	}
-	wf := float64(w)
-	hf := float64(h)
+	wf := float64(textureWidth)
+	hf := float64(textureHeight)
 	n := 0
 	for i := 0; i < l; i++ {
 		dx0, dy0, dx1, dy1 := parts.Dst(i)
@@ -67,39 +63,55 @@ func vertices(parts ImageParts, width, height int, geo *affine.GeoM) []float32 {
 		vs.SetIndex(n+7, g3)
 		vs.SetIndex(n+8, g4)
 		vs.SetIndex(n+9, g5)
+		vs.SetIndex(n+10, c[0][0])
+		vs.SetIndex(n+11, c[0][1])
+		vs.SetIndex(n+12, c[0][2])
+		vs.SetIndex(n+13, c[0][3])
 
-		vs.SetIndex(n+10, dx1)
-		vs.SetIndex(n+11, dy0)
-		vs.SetIndex(n+12, u1)
-		vs.SetIndex(n+13, v0)
-		vs.SetIndex(n+14, g0)
-		vs.SetIndex(n+15, g1)
-		vs.SetIndex(n+16, g2)
-		vs.SetIndex(n+17, g3)
-		vs.SetIndex(n+18, g4)
-		vs.SetIndex(n+19, g5)
+		vs.SetIndex(n+14, dx1)
+		vs.SetIndex(n+15, dy0)
+		vs.SetIndex(n+16, u1)
+		vs.SetIndex(n+17, v0)
+		vs.SetIndex(n+18, g0)
+		vs.SetIndex(n+19, g1)
+		vs.SetIndex(n+20, g2)
+		vs.SetIndex(n+21, g3)
+		vs.SetIndex(n+22, g4)
+		vs.SetIndex(n+23, g5)
+		vs.SetIndex(n+24, c[1][0])
+		vs.SetIndex(n+25, c[1][1])
+		vs.SetIndex(n+26, c[1][2])
+		vs.SetIndex(n+27, c[1][3])
 
-		vs.SetIndex(n+20, dx0)
-		vs.SetIndex(n+21, dy1)
-		vs.SetIndex(n+22, u0)
-		vs.SetIndex(n+23, v1)
-		vs.SetIndex(n+24, g0)
-		vs.SetIndex(n+25, g1)
-		vs.SetIndex(n+26, g2)
-		vs.SetIndex(n+27, g3)
-		vs.SetIndex(n+28, g4)
-		vs.SetIndex(n+29, g5)
+		vs.SetIndex(n+28, dx0)
+		vs.SetIndex(n+29, dy1)
+		vs.SetIndex(n+30, u0)
+		vs.SetIndex(n+31, v1)
+		vs.SetIndex(n+32, g0)
+		vs.SetIndex(n+33, g1)
+		vs.SetIndex(n+34, g2)
+		vs.SetIndex(n+35, g3)
+		vs.SetIndex(n+36, g4)
+		vs.SetIndex(n+37, g5)
+		vs.SetIndex(n+38, c[2][0])
+		vs.SetIndex(n+39, c[2][1])
+		vs.SetIndex(n+40, c[2][2])
+		vs.SetIndex(n+41, c[2][3])
 
-		vs.SetIndex(n+30, dx1)
-		vs.SetIndex(n+31, dy1)
-		vs.SetIndex(n+32, u1)
-		vs.SetIndex(n+33, v1)
-		vs.SetIndex(n+34, g0)
-		vs.SetIndex(n+35, g1)
-		vs.SetIndex(n+36, g2)
-		vs.SetIndex(n+37, g3)
-		vs.SetIndex(n+38, g4)
-		vs.SetIndex(n+39, g5)
+		vs.SetIndex(n+42, dx1)
+		vs.SetIndex(n+43, dy1)
+		vs.SetIndex(n+44, u1)
+		vs.SetIndex(n+45, v1)
+		vs.SetIndex(n+46, g0)
+		vs.SetIndex(n+47, g1)
+		vs.SetIndex(n+48, g2)
+		vs.SetIndex(n+49, g3)
+		vs.SetIndex(n+50, g4)
+		vs.SetIndex(n+51, g5)
+		vs.SetIndex(n+52, c[3][0])
+		vs.SetIndex(n+53, c[3][1])
+		vs.SetIndex(n+54, c[3][2])
+		vs.SetIndex(n+55, c[3][3])
 
 		n += quadFloat32Num
 	}
@@ -20,27 +20,23 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/affine"
 )
 
-func vertices(parts ImageParts, width, height int, geo *affine.GeoM) []float32 {
+func vertices(parts ImageParts, textureWidth, textureHeight int, geo *affine.GeoM, colors *[4][4]float32) []float32 {
 	// TODO: This function should be in graphics package?
 	l := parts.Len()
 	vs := make([]float32, l*quadFloat32Num)
-	g := geo.UnsafeElements()
+	c := colors
+	if c == nil {
+		c = &whiteVertexColors
+	}
+	g := geo.Elements()
 	g0 := float32(g[0])
 	g1 := float32(g[1])
 	g2 := float32(g[3])
 	g3 := float32(g[4])
 	g4 := float32(g[2])
 	g5 := float32(g[5])
-	w := 1
-	h := 1
-	for w < width {
-		w *= 2
-	}
-	for h < height {
-		h *= 2
-	}
-	wf := float32(w)
-	hf := float32(h)
+	wf := float32(textureWidth)
+	hf := float32(textureHeight)
 	n := 0
 	for i := 0; i < l; i++ {
 		dx0, dy0, dx1, dy1 := parts.Dst(i)
@@ -67,39 +63,55 @@ func vertices(parts ImageParts, width, height int, geo *affine.GeoM) []float32 {
 		vs[n+7] = g3
 		vs[n+8] = g4
 		vs[n+9] = g5
+		vs[n+10] = c[0][0]
+		vs[n+11] = c[0][1]
+		vs[n+12] = c[0][2]
+		vs[n+13] = c[0][3]
 
-		vs[n+10] = x1
-		vs[n+11] = y0
-		vs[n+12] = u1
-		vs[n+13] = v0
-		vs[n+14] = g0
-		vs[n+15] = g1
-		vs[n+16] = g2
-		vs[n+17] = g3
-		vs[n+18] = g4
-		vs[n+19] = g5
+		vs[n+14] = x1
+		vs[n+15] = y0
+		vs[n+16] = u1
+		vs[n+17] = v0
+		vs[n+18] = g0
+		vs[n+19] = g1
+		vs[n+20] = g2
+		vs[n+21] = g3
+		vs[n+22] = g4
+		vs[n+23] = g5
+		vs[n+24] = c[1][0]
+		vs[n+25] = c[1][1]
+		vs[n+26] = c[1][2]
+		vs[n+27] = c[1][3]
 
-		vs[n+20] = x0
-		vs[n+21] = y1
-		vs[n+22] = u0
-		vs[n+23] = v1
-		vs[n+24] = g0
-		vs[n+25] = g1
-		vs[n+26] = g2
-		vs[n+27] = g3
-		vs[n+28] = g4
-		vs[n+29] = g5
+		vs[n+28] = x0
+		vs[n+29] = y1
+		vs[n+30] = u0
+		vs[n+31] = v1
+		vs[n+32] = g0
+		vs[n+33] = g1
+		vs[n+34] = g2
+		vs[n+35] = g3
+		vs[n+36] = g4
+		vs[n+37] = g5
+		vs[n+38] = c[2][0]
+		vs[n+39] = c[2][1]
+		vs[n+40] = c[2][2]
+		vs[n+41] = c[2][3]
 
-		vs[n+30] = x1
-		vs[n+31] = y1
-		vs[n+32] = u1
-		vs[n+33] = v1
-		vs[n+34] = g0
-		vs[n+35] = g1
-		vs[n+36] = g2
-		vs[n+37] = g3
-		vs[n+38] = g4
-		vs[n+39] = g5
+		vs[n+42] = x1
+		vs[n+43] = y1
+		vs[n+44] = u1
+		vs[n+45] = v1
+		vs[n+46] = g0
+		vs[n+47] = g1
+		vs[n+48] = g2
+		vs[n+49] = g3
+		vs[n+50] = g4
+		vs[n+51] = g5
+		vs[n+52] = c[3][0]
+		vs[n+53] = c[3][1]
+		vs[n+54] = c[3][2]
+		vs[n+55] = c[3][3]
 
 		n += quadFloat32Num
 	}